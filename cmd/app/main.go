@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/rs/zerolog/log"
 
@@ -34,9 +37,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	// SIGINT/SIGTERM を受信したらキャンセルされるコンテキストでコマンドを実行
+	ctx, cancel := notifyContext(context.Background())
+	defer cancel()
+
 	// コマンドの実行
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		log.Error().Err(err).Msg("コマンドの実行に失敗しました")
+		cancel()
 		os.Exit(1)
 	}
 }
+
+// notifyContext は SIGINT/SIGTERM を受信した時点でキャンセルされるコンテキストを返します
+// 実行中の処理はコンテキストのキャンセルを検知して中断し、トランザクションのロールバックと実行結果の記録を行います
+// 2回目のシグナルではキャンセルを待たずに強制終了します
+func notifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			log.Warn().Str("signal", sig.String()).Msg("シグナルを受信したため、実行中の処理を中断します（もう一度送信すると強制終了します）")
+			// シグナルの捕捉を解除し、2回目のシグナルでは既定の動作（強制終了）にする
+			signal.Stop(signals)
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(signals)
+		}
+	}()
+
+	return ctx, cancel
+}
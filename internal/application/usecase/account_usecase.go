@@ -10,12 +10,74 @@ import (
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/service"
 )
 
+// 同期モード
+const (
+	SyncModeFull = "full" // 全件同期
+	SyncModeDiff = "diff" // 差分同期
+)
+
+// AccountSyncOptions はアカウント同期のオプションです
+type AccountSyncOptions struct {
+	Source string // アカウントの取得元（api1 または api2、空の場合は api1）
+	Mode   string // 同期モード（full または diff、空の場合は full）
+
+	// Prune が true の場合、上流から削除されたアカウントを論理削除ではなく物理削除します
+	Prune bool
+}
+
+// Validate はオプションの値を検証します
+func (o AccountSyncOptions) Validate() error {
+	switch o.Source {
+	case "", entity.SourceAPI1, entity.SourceAPI2:
+	default:
+		return fmt.Errorf("未対応のアカウント取得元です: %s（%s, %s のいずれかを指定してください）", o.Source, entity.SourceAPI1, entity.SourceAPI2)
+	}
+	switch o.Mode {
+	case "", SyncModeFull, SyncModeDiff:
+	default:
+		return fmt.Errorf("不正な同期モードです: %s（%s または %s を指定してください）", o.Mode, SyncModeFull, SyncModeDiff)
+	}
+	return nil
+}
+
+// source はアカウントの取得元を返します（未指定の場合は api1）
+func (o AccountSyncOptions) source() string {
+	if o.Source == "" {
+		return entity.SourceAPI1
+	}
+	return o.Source
+}
+
+// process は通知に表示するコマンド名を返します
+func (o AccountSyncOptions) process() string {
+	process := "account sync"
+	if o.Mode == SyncModeDiff {
+		process += " --mode diff"
+	}
+	if o.source() != entity.SourceAPI1 {
+		process += " --source " + o.source()
+	}
+	if o.Prune {
+		process += " --prune"
+	}
+	return process
+}
+
+// accountFetcher は外部APIからアカウント情報をページ単位で取得します
+// ExternalAPI1AccountRepository と ExternalAPI2AccountRepository の共通部分です
+type accountFetcher interface {
+	StreamAccounts(ctx context.Context, handler func(accounts []entity.Account) error) (int, error)
+}
+
 // AccountUseCase はアカウント関連のユースケースを実装します
 type AccountUseCase struct {
 	accountRepo      repository.MySQLAccountRepository
 	accountAPIRepo   repository.ExternalAPI1AccountRepository
+	accountAPI2Repo  repository.ExternalAPI2AccountRepository
+	runRepo          repository.SyncRunRepository
 	notificationRepo repository.NotificationRepository
 }
 
@@ -23,58 +85,180 @@ type AccountUseCase struct {
 func NewAccountUseCase(
 	accountRepo repository.MySQLAccountRepository,
 	accountAPIRepo repository.ExternalAPI1AccountRepository,
+	accountAPI2Repo repository.ExternalAPI2AccountRepository,
+	runRepo repository.SyncRunRepository,
 	notificationRepo repository.NotificationRepository,
 ) *AccountUseCase {
 	return &AccountUseCase{
 		accountRepo:      accountRepo,
 		accountAPIRepo:   accountAPIRepo,
+		accountAPI2Repo:  accountAPI2Repo,
+		runRepo:          runRepo,
 		notificationRepo: notificationRepo,
 	}
 }
 
 // SyncAccounts は外部APIからアカウント情報を取得し、データベースに同期します
 func (uc *AccountUseCase) SyncAccounts(ctx context.Context) error {
-	log.Info().Msg("アカウント情報の同期を開始します")
+	return uc.SyncAccountsWithOptions(ctx, AccountSyncOptions{Mode: SyncModeFull})
+}
+
+// SyncAccountsDiff は外部APIとデータベースのアカウント情報を比較し、新規・変更のあったアカウントのみを同期します
+func (uc *AccountUseCase) SyncAccountsDiff(ctx context.Context) error {
+	return uc.SyncAccountsWithOptions(ctx, AccountSyncOptions{Mode: SyncModeDiff})
+}
+
+// SyncAccountsWithOptions はオプションで指定された取得元・同期モードでアカウント情報を同期します
+func (uc *AccountUseCase) SyncAccountsWithOptions(ctx context.Context, opts AccountSyncOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
 
 	// コマンド実行結果の記録を開始
-	result := model.NewCommandResult("account sync")
+	result := model.NewCommandResult(opts.process())
 
-	// 外部APIからアカウント情報を取得
-	accounts, err := uc.accountAPIRepo.FetchAccounts(ctx)
+	err := uc.syncAccounts(ctx, opts, result)
+	if err != nil {
+		result.SetError(err)
+	}
+
+	// 実行履歴を保存し、通知を送信
+	recordCommandResult(ctx, uc.runRepo, uc.notificationRepo, result)
+	return err
+}
+
+// syncAccounts は同期モードに応じてアカウント情報を同期し、処理結果を result に記録します
+func (uc *AccountUseCase) syncAccounts(ctx context.Context, opts AccountSyncOptions, result *model.CommandResult) error {
+	fetcher := uc.accountFetcher(opts.source())
+	var fetchedIDs []uint
+	var err error
+	if opts.Mode == SyncModeDiff {
+		fetchedIDs, err = uc.syncAccountsDiff(ctx, fetcher, opts.source(), result)
+	} else {
+		fetchedIDs, err = uc.syncAccountsFull(ctx, fetcher, result)
+	}
 	if err != nil {
-		log.Error().Err(err).Msg("アカウント情報の取得に失敗しました")
-		result.SetFailed()
-		result.Complete()
-		if notifyErr := uc.notificationRepo.NotifyCommandResult(result); notifyErr != nil {
-			log.Error().Err(notifyErr).Msg("通知の送信に失敗しました")
-		}
 		return err
 	}
 
-	log.Info().Int("count", len(accounts)).Msg("アカウント情報を取得しました")
+	// 全件取得できた場合のみ、上流から削除されたアカウントを削除する
+	return uc.removeMissingAccounts(ctx, opts.source(), fetchedIDs, opts.Prune, result)
+}
 
-	// データベースに保存
-	if err := uc.accountRepo.SaveAll(ctx, accounts); err != nil {
-		log.Error().Err(err).Msg("アカウント情報の保存に失敗しました")
-		result.SetFailed()
-		result.Complete()
-		if notifyErr := uc.notificationRepo.NotifyCommandResult(result); notifyErr != nil {
-			log.Error().Err(notifyErr).Msg("通知の送信に失敗しました")
-		}
+// removeMissingAccounts は取得元のアカウントのうち、上流から取得できなかったアカウントを削除し、削除件数を result に記録します
+// 上流のアカウントが0件の場合は、APIの異常で全件を削除してしまわないように削除を行いません
+func (uc *AccountUseCase) removeMissingAccounts(ctx context.Context, source string, fetchedIDs []uint, prune bool, result *model.CommandResult) error {
+	if len(fetchedIDs) == 0 {
+		log.Warn().Str("source", source).Msg("上流のアカウントが0件のため、削除されたアカウントの検出を行いません")
+		return nil
+	}
+
+	removed, err := uc.accountRepo.RemoveMissingBySource(ctx, source, fetchedIDs, prune)
+	if err != nil {
+		log.Error().Err(err).Str("source", source).Msg("上流から削除されたアカウントの削除に失敗しました")
 		return err
 	}
+	result.AddRemovedCount(removed)
+	return nil
+}
+
+// accountFetcher は取得元に応じたアカウント取得用のリポジトリを返します
+func (uc *AccountUseCase) accountFetcher(source string) accountFetcher {
+	if source == entity.SourceAPI2 {
+		return uc.accountAPI2Repo
+	}
+	return uc.accountAPIRepo
+}
 
-	// 処理結果を記録
-	result.AddCounts(len(accounts), 0, len(accounts))
-	result.Complete()
+// syncAccountsFull は全アカウント情報を同期し、処理結果を result に記録します
+// 上流から取得したアカウントのIDを返します
+func (uc *AccountUseCase) syncAccountsFull(ctx context.Context, fetcher accountFetcher, result *model.CommandResult) ([]uint, error) {
+	log.Info().Msg("アカウント情報の同期を開始します")
 
-	// 通知を送信
-	if err := uc.notificationRepo.NotifyCommandResult(result); err != nil {
-		log.Error().Err(err).Msg("通知の送信に失敗しました")
+	// 外部APIからアカウント情報をページ単位で取得し、ページごとにデータベースに保存
+	count := 0
+	var saved repository.UpsertResult
+	var fetchedIDs []uint
+	pages, err := fetcher.StreamAccounts(ctx, func(accounts []entity.Account) error {
+		if len(accounts) == 0 {
+			return nil
+		}
+		upserted, err := uc.accountRepo.SaveAll(ctx, accounts)
+		if err != nil {
+			return fmt.Errorf("アカウント情報の保存に失敗しました: %w", err)
+		}
+		saved.Add(upserted)
+		count += len(accounts)
+		fetchedIDs = append(fetchedIDs, accountIDs(accounts)...)
+		return nil
+	})
+	result.AddPageCount(pages)
+
+	// 処理結果を記録（途中で失敗した場合も保存済みのアカウントの件数を記録する）
+	result.AddCounts(count, 0, count)
+	result.AddDiffCounts(saved.Inserted, saved.Updated, 0)
+
+	if err != nil {
+		log.Error().Err(err).Int("pages", pages).Int("saved", count).Msg("アカウント情報の同期に失敗しました")
+		return nil, err
 	}
 
+	log.Info().Int("count", count).Int("pages", pages).Int("inserted", saved.Inserted).Int("updated", saved.Updated).Msg("アカウント情報を取得しました")
+
 	log.Info().Msg("アカウント情報の同期が完了しました")
-	return nil
+	return fetchedIDs, nil
+}
+
+// syncAccountsDiff はアカウント情報を差分同期し、処理結果を result に記録します
+// 差分は同じ取得元のアカウント同士で比較します
+// 上流から取得したアカウントのIDを返します
+func (uc *AccountUseCase) syncAccountsDiff(ctx context.Context, fetcher accountFetcher, source string, result *model.CommandResult) ([]uint, error) {
+	log.Info().Msg("アカウント情報の差分同期を開始します")
+
+	// 外部APIからアカウント情報を取得（差分検出のため全ページを読み込む）
+	var accounts []entity.Account
+	pages, err := fetcher.StreamAccounts(ctx, func(page []entity.Account) error {
+		accounts = append(accounts, page...)
+		return nil
+	})
+	result.AddPageCount(pages)
+	if err != nil {
+		log.Error().Err(err).Int("pages", pages).Msg("アカウント情報の取得に失敗しました")
+		return nil, err
+	}
+
+	// データベースから既存のアカウント情報を取得
+	existing, err := uc.accountRepo.FindAll(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("既存アカウント情報の取得に失敗しました")
+		return nil, err
+	}
+
+	// 差分を検出
+	diff := service.DiffAccounts(filterAccountsBySource(existing, source), accounts)
+	log.Info().
+		Int("fetched", len(accounts)).
+		Int("pages", pages).
+		Int("inserted", len(diff.Inserted)).
+		Int("updated", len(diff.Updated)).
+		Int("unchanged", len(diff.Unchanged)).
+		Msg("アカウント情報の差分を検出しました")
+
+	// 新規・変更のあったアカウントのみ保存
+	changed := diff.Changed()
+	if len(changed) > 0 {
+		if _, err := uc.accountRepo.SaveAll(ctx, changed); err != nil {
+			log.Error().Err(err).Msg("アカウント情報の保存に失敗しました")
+			return nil, err
+		}
+	}
+
+	// 処理結果を記録
+	result.AddCounts(len(accounts), 0, len(changed))
+	result.AddDiffCounts(len(diff.Inserted), len(diff.Updated), len(diff.Unchanged))
+
+	log.Info().Msg("アカウント情報の差分同期が完了しました")
+	return accountIDs(accounts), nil
 }
 
 // SyncAccountsByIDs は指定されたアカウントIDのアカウント情報を同期します
@@ -94,9 +278,16 @@ func (uc *AccountUseCase) SyncAccountsByIDs(ctx context.Context, accountIDs []in
 	successCount := 0
 	errorCount := 0
 	totalRecords := 0
+	var interruptErr error
 
 	// 各アカウントIDについて処理
 	for _, accountID := range accountIDs {
+		// 中断された場合は残りのアカウントを処理しない
+		if err := ctx.Err(); err != nil {
+			interruptErr = fmt.Errorf("アカウント情報の同期を中断しました: %w", err)
+			break
+		}
+
 		// 外部APIからアカウント情報を取得
 		account, err := uc.accountAPIRepo.FetchAccountByID(ctx, accountID)
 		if err != nil {
@@ -119,28 +310,44 @@ func (uc *AccountUseCase) SyncAccountsByIDs(ctx context.Context, accountIDs []in
 	// 処理結果を記録
 	result.AddCounts(successCount, errorCount, totalRecords)
 
-	if errorCount > 0 && successCount == 0 {
-		result.SetFailed()
+	err := interruptErr
+	if err == nil && errorCount > 0 && successCount == 0 {
+		err = fmt.Errorf("すべてのアカウント情報の同期に失敗しました")
 	}
-
-	result.Complete()
-
-	// 通知を送信
-	if err := uc.notificationRepo.NotifyCommandResult(result); err != nil {
-		log.Error().Err(err).Msg("通知の送信に失敗しました")
+	if err != nil {
+		result.SetError(err)
 	}
 
+	// 実行履歴を保存し、通知を送信
+	recordCommandResult(ctx, uc.runRepo, uc.notificationRepo, result)
+
 	log.Info().
 		Int("success", successCount).
 		Int("error", errorCount).
 		Int("total", len(accountIDs)).
 		Msg("指定されたアカウント情報の同期が完了しました")
 
-	if errorCount > 0 && successCount == 0 {
-		return fmt.Errorf("すべてのアカウント情報の同期に失敗しました")
+	return err
+}
+
+// filterAccountsBySource は指定された取得元のアカウントのみを返します
+func filterAccountsBySource(accounts []entity.Account, source string) []entity.Account {
+	filtered := make([]entity.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Source == source {
+			filtered = append(filtered, account)
+		}
 	}
+	return filtered
+}
 
-	return nil
+// accountIDs はアカウントのIDを返します
+func accountIDs(accounts []entity.Account) []uint {
+	ids := make([]uint, len(accounts))
+	for i, account := range accounts {
+		ids[i] = account.ID
+	}
+	return ids
 }
 
 // GetAllAccounts は全てのアカウント情報を取得します
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// AuthorizeAPI2Options は外部API2のOAuth2認可のオプションです
+type AuthorizeAPI2Options struct {
+	Port         int              // リダイレクトを受け取るローカルポート（0の場合は空いているポート）
+	ClientID     string           // OAuth2クライアントID（空の場合は設定・Secret Managerの値）
+	ClientSecret string           // OAuth2クライアントシークレット（空の場合は設定・Secret Managerの値）
+	Save         bool             // 取得したリフレッシュトークンをシークレットに保存するかどうか
+	OnAuthURL    func(url string) // ブラウザで開く認可URLが決まったときに呼び出されます
+}
+
+// AuthUseCase は外部APIの認可関連のユースケースを実装します
+type AuthUseCase struct {
+	api2Authorizer repository.ExternalAPI2Authorizer
+}
+
+// NewAuthUseCase は AuthUseCase の新しいインスタンスを作成します
+func NewAuthUseCase(api2Authorizer repository.ExternalAPI2Authorizer) *AuthUseCase {
+	return &AuthUseCase{
+		api2Authorizer: api2Authorizer,
+	}
+}
+
+// AuthorizeAPI2 は外部API2のOAuth2認可を行い、リフレッシュトークンを返します
+// opts.Save が指定されている場合は認証情報をシークレットに保存します
+func (uc *AuthUseCase) AuthorizeAPI2(ctx context.Context, opts AuthorizeAPI2Options) (string, error) {
+	credentials, err := uc.api2Authorizer.Authorize(ctx, repository.ExternalAPI2AuthorizeOptions{
+		Port:         opts.Port,
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		OnAuthURL:    opts.OnAuthURL,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("ExternalAPI2のOAuth2認可に失敗しました")
+		return "", err
+	}
+
+	if opts.Save {
+		if err := uc.api2Authorizer.SaveCredentials(ctx, credentials); err != nil {
+			log.Error().Err(err).Msg("OAuth2認証情報の保存に失敗しました")
+			// 保存に失敗してもリフレッシュトークンは表示できるように返す
+			return credentials.RefreshToken, err
+		}
+	}
+
+	return credentials.RefreshToken, nil
+}
@@ -2,41 +2,212 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
 )
 
+// 並列処理数の設定値
+const (
+	DefaultParallel = 5  // デフォルトの並列処理数
+	MinParallel     = 1  // 並列処理数の下限
+	MaxParallel     = 10 // 並列処理数の上限
+)
+
+// WatermarkOverlap は差分取得で基準日時から遡って再取得する幅です
+// 外部API1の updated_since が基準日時ちょうどに更新されたキャンペーンを含むかどうかは仕様で定められていないため、
+// 基準日時より前から取得し直し、境界の扱いに関わらず取りこぼさないようにします（保存は upsert のため重複しても問題ありません）
+const WatermarkOverlap = time.Minute
+
+// CampaignSyncOptions はキャンペーン同期のオプションです
+type CampaignSyncOptions struct {
+	Source     string   // キャンペーンの取得元（api1 または api2、空の場合は api1）
+	AccountIDs []uint   // 同期対象のアカウントID（空の場合は全アカウント）
+	Statuses   []string // 保存対象のキャンペーンステータス（空の場合は全ステータス）
+	Parallel   int      // 同時に処理するアカウント数（1-10）
+
+	// ContinueOnError が true の場合、一部のアカウントで失敗しても他のアカウントの処理を継続します
+	// false の場合は最初の失敗で処理を中断します（それまでに取得に成功したアカウントのキャンペーンは保存済みになります）
+	ContinueOnError bool
+	// FailureThreshold は許容する失敗アカウントの割合（0.0〜1.0）です
+	// 失敗率がこの値を超えた場合はエラーを返します
+	FailureThreshold float64
+
+	// Prune が true の場合、上流から削除されたキャンペーンを論理削除ではなく物理削除します
+	Prune bool
+
+	// ResumeRunID は再開する実行履歴のIDです（0の場合は全てのアカウントを同期します）
+	// 指定した実行でキャンペーンの同期が完了したアカウントを読み飛ばします
+	ResumeRunID uint
+
+	// Full が true の場合、差分同期の基準日時を使用せずに全てのキャンペーンを取得し、基準日時を取り直します
+	// Statuses と同時に指定した場合は、保存しなかったキャンペーンを次回取得できるよう基準日時を削除します
+	// false の場合は、基準日時が記録されているアカウントについて、その日時以降に更新されたキャンペーンのみを取得します
+	Full bool
+}
+
+// Validate はオプションの値を検証します
+func (o CampaignSyncOptions) Validate() error {
+	switch o.Source {
+	case "", entity.SourceAPI1, entity.SourceAPI2:
+	default:
+		return fmt.Errorf("未対応のキャンペーン取得元です: %s（%s, %s のいずれかを指定してください）", o.Source, entity.SourceAPI1, entity.SourceAPI2)
+	}
+	if err := ValidateParallel(o.Parallel); err != nil {
+		return err
+	}
+	if o.FailureThreshold < 0 || o.FailureThreshold > 1 {
+		return fmt.Errorf("失敗率の閾値は0.0〜1.0の範囲で指定してください: %g", o.FailureThreshold)
+	}
+	for _, id := range o.AccountIDs {
+		if id == 0 {
+			return fmt.Errorf("アカウントIDに0は指定できません")
+		}
+	}
+	return nil
+}
+
+// source はキャンペーンの取得元を返します（未指定の場合は api1）
+func (o CampaignSyncOptions) source() string {
+	if o.Source == "" {
+		return entity.SourceAPI1
+	}
+	return o.Source
+}
+
+// ValidateParallel は並列処理数が許容範囲内かどうかを検証します
+func ValidateParallel(parallel int) error {
+	if parallel < MinParallel || parallel > MaxParallel {
+		return fmt.Errorf("並列処理数は%d〜%dの範囲で指定してください: %d", MinParallel, MaxParallel, parallel)
+	}
+	return nil
+}
+
+// campaignFetcher は外部APIからキャンペーン情報をページ単位で取得します
+// ExternalAPI1CampaignRepository と ExternalAPI2CampaignRepository の共通部分です
+type campaignFetcher interface {
+	StreamCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time, handler func(campaigns []entity.Campaign) error) (int, error)
+}
+
+// accountCampaigns は1アカウント分の外部APIからのキャンペーンの取得結果です
+type accountCampaigns struct {
+	accountID     uint
+	campaigns     []entity.Campaign // ステータスで絞り込んだ保存対象のキャンペーン
+	ids           []uint            // ステータスで絞り込む前の全てのキャンペーンのID
+	watermark     *time.Time        // 記録済みの差分同期の基準日時（全件を取得した場合は nil）
+	updatedSince  *time.Time        // 外部APIに渡した取得開始日時（基準日時から WatermarkOverlap だけ遡った日時、全件を取得した場合は nil）
+	highWaterMark time.Time         // 取得したキャンペーンの更新日時の最大値（更新日時がない場合はゼロ値）
+}
+
 // CampaignUseCase はキャンペーン関連のユースケースを実装します
 type CampaignUseCase struct {
-	campaignRepo    repository.MySQLCampaignRepository
-	campaignAPIRepo repository.ExternalAPI1CampaignRepository
-	accountRepo     repository.MySQLAccountRepository
+	campaignRepo     repository.MySQLCampaignRepository
+	campaignAPIRepo  repository.ExternalAPI1CampaignRepository
+	campaignAPI2Repo repository.ExternalAPI2CampaignRepository
+	accountRepo      repository.MySQLAccountRepository
+	txManager        repository.TransactionManager
+	runRepo          repository.SyncRunRepository
+	notificationRepo repository.NotificationRepository
 }
 
 // NewCampaignUseCase は CampaignUseCase の新しいインスタンスを作成します
 func NewCampaignUseCase(
 	campaignRepo repository.MySQLCampaignRepository,
 	campaignAPIRepo repository.ExternalAPI1CampaignRepository,
+	campaignAPI2Repo repository.ExternalAPI2CampaignRepository,
 	accountRepo repository.MySQLAccountRepository,
+	txManager repository.TransactionManager,
+	runRepo repository.SyncRunRepository,
+	notificationRepo repository.NotificationRepository,
 ) *CampaignUseCase {
 	return &CampaignUseCase{
-		campaignRepo:    campaignRepo,
-		campaignAPIRepo: campaignAPIRepo,
-		accountRepo:     accountRepo,
+		campaignRepo:     campaignRepo,
+		campaignAPIRepo:  campaignAPIRepo,
+		campaignAPI2Repo: campaignAPI2Repo,
+		accountRepo:      accountRepo,
+		txManager:        txManager,
+		runRepo:          runRepo,
+		notificationRepo: notificationRepo,
 	}
 }
 
 // SyncCampaigns は全てのアカウントに対して、外部APIからキャンペーン情報を取得し、データベースに同期します
 func (uc *CampaignUseCase) SyncCampaigns(ctx context.Context) error {
-	log.Info().Msg("キャンペーン情報の同期を開始します")
+	return uc.SyncCampaignsWithOptions(ctx, CampaignSyncOptions{Parallel: DefaultParallel})
+}
+
+// SyncCampaignsWithOptions はオプションで指定されたアカウント・ステータスに絞り込んでキャンペーン情報を同期します
+func (uc *CampaignUseCase) SyncCampaignsWithOptions(ctx context.Context, opts CampaignSyncOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	process := "campaign sync"
+	if opts.source() != entity.SourceAPI1 {
+		process += " --source " + opts.source()
+	}
+	if opts.Prune {
+		process += " --prune"
+	}
+	if opts.Full {
+		process += " --full"
+	}
 
-	// アカウント情報を取得
-	accounts, err := uc.accountRepo.FindAll(ctx)
+	// 再開する実行でキャンペーンの同期が完了したアカウントを取得
+	var completedIDs []uint
+	if opts.ResumeRunID != 0 {
+		var err error
+		completedIDs, err = findCompletedAccountIDs(ctx, uc.runRepo, opts.ResumeRunID, process)
+		if err != nil {
+			return err
+		}
+	}
+
+	// コマンド実行結果の記録を開始
+	result := startCommandResult(ctx, uc.runRepo, process)
+
+	err := uc.syncCampaigns(ctx, opts, completedIDs, result)
+	if err != nil {
+		result.SetError(err)
+	}
+
+	// 実行履歴を保存し、通知を送信
+	recordCommandResult(ctx, uc.runRepo, uc.notificationRepo, result)
+	return err
+}
+
+// syncCampaigns はキャンペーン情報を同期し、処理結果を result に記録します
+// キャンペーンはアカウントごとに保存し、保存が完了したアカウントを result の実行履歴のチェックポイントとして記録します
+// completedIDs に含まれるアカウント（再開する実行で同期が完了したアカウント）は読み飛ばします
+func (uc *CampaignUseCase) syncCampaigns(ctx context.Context, opts CampaignSyncOptions, completedIDs []uint, result *model.CommandResult) error {
+	log.Info().
+		Str("source", opts.source()).
+		Uints("account_ids", opts.AccountIDs).
+		Strs("statuses", opts.Statuses).
+		Int("parallel", opts.Parallel).
+		Bool("continue_on_error", opts.ContinueOnError).
+		Float64("failure_threshold", opts.FailureThreshold).
+		Bool("prune", opts.Prune).
+		Uint("resume_run_id", opts.ResumeRunID).
+		Bool("full", opts.Full).
+		Msg("キャンペーン情報の同期を開始します")
+
+	// 取得元に応じたリポジトリを選択
+	fetcher := uc.campaignFetcher(opts.source())
+
+	// 同期対象のアカウント情報を取得
+	accounts, err := uc.findTargetAccounts(ctx, opts.AccountIDs, opts.source())
 	if err != nil {
 		log.Error().Err(err).Msg("アカウント情報の取得に失敗しました")
 		return err
@@ -44,27 +215,138 @@ func (uc *CampaignUseCase) SyncCampaigns(ctx context.Context) error {
 
 	log.Info().Int("account_count", len(accounts)).Msg("アカウント情報を取得しました")
 
-	// 並列処理のためのエラーグループを作成
-	g, ctx := errgroup.WithContext(ctx)
+	if len(opts.AccountIDs) > 0 {
+		result.SetAccountIDs(formatAccountIDs(opts.AccountIDs))
+	}
+
+	// 再開する実行で同期が完了したアカウントを読み飛ばす
+	if opts.ResumeRunID != 0 {
+		var skipped int
+		accounts, skipped = skipCompletedAccounts(accounts, completedIDs)
+		log.Info().
+			Uint("resume_run_id", opts.ResumeRunID).
+			Int("skipped_accounts", skipped).
+			Int("remaining_accounts", len(accounts)).
+			Msg("同期が完了したアカウントを読み飛ばして再開します")
+
+		// 今回の実行からも再開できるよう、完了済みのアカウントを今回の実行のチェックポイントとして引き継ぐ
+		if err := uc.saveCheckpoints(ctx, result.RunID, completedIDs); err != nil {
+			return err
+		}
+	}
+
+	// 差分同期の基準日時を取得（--full の場合は全件を取得するため使用しない）
+	watermarks := map[uint]time.Time{}
+	if !opts.Full {
+		watermarks, err = uc.campaignRepo.FindWatermarks(ctx, opts.source())
+		if err != nil {
+			log.Error().Err(err).Msg("差分同期の基準日時の取得に失敗しました")
+			return err
+		}
+	}
+
+	// 並列処理のためのエラーグループを作成（同時実行数を制限）
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.Parallel)
 	var mu sync.Mutex
-	allCampaigns := make([]entity.Campaign, 0)
+	// 保存は1アカウントずつ行う（--atomic では全てのアカウントで1つのトランザクションを共有するため）
+	var saveMu sync.Mutex
+	var succeededIDs, failedIDs []uint
+	// 他のアカウントの失敗やシグナルの受信による中断で取得を打ち切ったアカウント（失敗として数えない）
+	var abortedIDs []uint
+	var saved repository.UpsertResult
+	totalCampaigns := 0
+	totalRemoved := 0
+	totalPages := 0
 
-	// 各アカウントに対して並列処理
+	// 各アカウントに対して並列処理（上限に達している場合は空きが出るまで待機）
 	for _, account := range accounts {
+		// 中断された場合（シグナルの受信や他のアカウントの失敗）は残りのアカウントの処理を開始しない
+		if gctx.Err() != nil {
+			break
+		}
+
 		account := account // ゴルーチン内で使用するためにローカル変数にコピー
 		g.Go(func() error {
-			// 外部APIからキャンペーン情報を取得
-			campaigns, err := uc.campaignAPIRepo.FetchCampaignsByAccountID(ctx, account.ID)
+			// 外部APIからキャンペーン情報をページ単位で取得し、ステータスで絞り込み
+			// 基準日時が記録されているアカウントは、基準日時から WatermarkOverlap だけ遡った日時以降に更新されたキャンペーンのみを取得する
+			// 削除の検出と基準日時の更新にはステータスで絞り込む前の全てのキャンペーンを使用する
+			fetched := accountCampaigns{accountID: account.ID}
+			if mark, ok := watermarks[account.ID]; ok {
+				since := mark.Add(-WatermarkOverlap)
+				fetched.watermark = &mark
+				fetched.updatedSince = &since
+			}
+			pages, err := fetcher.StreamCampaignsByAccountID(gctx, account.ID, fetched.updatedSince, func(page []entity.Campaign) error {
+				for _, campaign := range page {
+					fetched.ids = append(fetched.ids, campaign.ID)
+					if campaign.UpdatedAt.After(fetched.highWaterMark) {
+						fetched.highWaterMark = campaign.UpdatedAt
+					}
+				}
+				fetched.campaigns = append(fetched.campaigns, filterCampaignsByStatus(page, opts.Statuses)...)
+				return nil
+			})
+
+			mu.Lock()
+			totalPages += pages
+			mu.Unlock()
+
 			if err != nil {
-				log.Error().Err(err).Uint("account_id", account.ID).Msg("キャンペーン情報の取得に失敗しました")
+				// 中断によるキャンセルはこのアカウントの失敗ではないため、処理しなかったアカウントとして記録する
+				if errors.Is(err, context.Canceled) && gctx.Err() != nil {
+					log.Warn().Uint("account_id", account.ID).Int("pages", pages).Msg("中断されたため、キャンペーン情報の取得を打ち切りました")
+
+					mu.Lock()
+					abortedIDs = append(abortedIDs, account.ID)
+					mu.Unlock()
+					return nil
+				}
+
+				log.Error().Err(err).Uint("account_id", account.ID).Int("pages", pages).Msg("キャンペーン情報の取得に失敗しました")
+
+				mu.Lock()
+				failedIDs = append(failedIDs, account.ID)
+				mu.Unlock()
+
+				// 失敗を許容するモードでは他のアカウントの処理を継続
+				if opts.ContinueOnError {
+					return nil
+				}
 				return err
 			}
 
-			log.Info().Uint("account_id", account.ID).Int("campaign_count", len(campaigns)).Msg("キャンペーン情報を取得しました")
+			logEvent := log.Info().Uint("account_id", account.ID).Int("campaign_count", len(fetched.ids)).Int("pages", pages)
+			if fetched.updatedSince != nil {
+				logEvent = logEvent.Time("updated_since", *fetched.updatedSince)
+			}
+			logEvent.Msg("キャンペーン情報を取得しました")
+
+			// 取得に成功したアカウントのキャンペーンを保存し、チェックポイントを記録
+			// 他のアカウントが失敗しても取得済みのキャンペーンは保存するため、gctx ではなく ctx を使用する
+			saveMu.Lock()
+			upserted, removed, err := uc.saveAccountCampaigns(ctx, result.RunID, fetched, opts)
+			saveMu.Unlock()
+			if err != nil {
+				log.Error().Err(err).Uint("account_id", account.ID).Msg("アカウントのキャンペーン情報の保存処理に失敗しました")
+
+				mu.Lock()
+				failedIDs = append(failedIDs, account.ID)
+				mu.Unlock()
+
+				// 取得の失敗と同様に、失敗を許容するモードでは他のアカウントの処理を継続
+				if opts.ContinueOnError {
+					return nil
+				}
+				return err
+			}
 
 			// 結果をマージ
 			mu.Lock()
-			allCampaigns = append(allCampaigns, campaigns...)
+			saved.Add(upserted)
+			totalCampaigns += len(fetched.campaigns)
+			totalRemoved += removed
+			succeededIDs = append(succeededIDs, account.ID)
 			mu.Unlock()
 
 			return nil
@@ -72,24 +354,224 @@ func (uc *CampaignUseCase) SyncCampaigns(ctx context.Context) error {
 	}
 
 	// 全ての並列処理が完了するのを待つ
-	if err := g.Wait(); err != nil {
-		log.Error().Err(err).Msg("キャンペーン情報の同期中にエラーが発生しました")
-		return err
+	waitErr := g.Wait()
+
+	// アカウントごとの処理結果を記録（途中で失敗した場合も保存済みのアカウントの件数を記録する）
+	sort.Slice(failedIDs, func(i, j int) bool { return failedIDs[i] < failedIDs[j] })
+	result.SetFailedAccountIDs(formatAccountIDs(failedIDs))
+	result.AddPageCount(totalPages)
+	result.AddCounts(len(succeededIDs), len(failedIDs), totalCampaigns)
+	result.AddDiffCounts(saved.Inserted, saved.Updated, 0)
+	result.AddRemovedCount(totalRemoved)
+
+	if len(abortedIDs) > 0 {
+		sort.Slice(abortedIDs, func(i, j int) bool { return abortedIDs[i] < abortedIDs[j] })
+		log.Warn().Uints("account_ids", abortedIDs).Msg("中断により同期しなかったアカウントがあります")
 	}
 
-	// データベースに保存
-	if len(allCampaigns) > 0 {
-		if err := uc.campaignRepo.SaveAll(ctx, allCampaigns); err != nil {
-			log.Error().Err(err).Msg("キャンペーン情報の保存に失敗しました")
+	if waitErr != nil {
+		log.Error().Err(waitErr).Int("succeeded_accounts", len(succeededIDs)).Msg("キャンペーン情報の同期中にエラーが発生しました")
+		return waitErr
+	}
+
+	// 失敗を許容するモードで中断された場合も、未処理のアカウントが残っているためエラーとする
+	if err := ctx.Err(); err != nil {
+		log.Warn().Int("succeeded_accounts", len(succeededIDs)).Msg("キャンペーン情報の同期を中断しました")
+		return fmt.Errorf("キャンペーン情報の同期を中断しました: %w", err)
+	}
+
+	log.Info().
+		Int("total_campaigns", totalCampaigns).
+		Int("total_pages", totalPages).
+		Int("removed_campaigns", totalRemoved).
+		Int("succeeded_accounts", len(succeededIDs)).
+		Int("failed_accounts", len(failedIDs)).
+		Msg("キャンペーン情報の同期が完了しました")
+
+	// 失敗率が閾値を超えた場合はエラーとする
+	// master では result にアカウント同期の件数も含まれるため、今回同期したアカウントの件数から失敗率を求める
+	if len(failedIDs) > 0 {
+		failureRate := float64(len(failedIDs)) / float64(len(accounts))
+		if failureRate > opts.FailureThreshold {
+			return fmt.Errorf("キャンペーン情報の同期に失敗したアカウントの割合が閾値を超えました: %d/%d件（%.0f%%、閾値: %.0f%%）",
+				len(failedIDs), len(accounts), failureRate*100, opts.FailureThreshold*100)
+		}
+	}
+
+	return nil
+}
+
+// saveAccountCampaigns は1アカウント分のキャンペーンを保存し、上流から削除されたキャンペーンの削除、
+// 差分同期の基準日時の更新、チェックポイントの記録を行います
+// チェックポイントと基準日時がキャンペーンの保存状態と一致するよう、これらを1つのトランザクションで実行します
+func (uc *CampaignUseCase) saveAccountCampaigns(ctx context.Context, runID uint, fetched accountCampaigns, opts CampaignSyncOptions) (repository.UpsertResult, int, error) {
+	var saved repository.UpsertResult
+	var removed int
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if len(fetched.campaigns) > 0 {
+			saved, err = uc.campaignRepo.SaveAll(ctx, fetched.campaigns)
+			if err != nil {
+				return fmt.Errorf("キャンペーン情報の保存に失敗しました: %w", err)
+			}
+		}
+
+		// 差分取得では更新されていないキャンペーンは返らないため、全件を取得した場合のみ削除を検出する
+		if fetched.updatedSince == nil {
+			removed, err = uc.campaignRepo.RemoveMissingByAccountID(ctx, opts.source(), fetched.accountID, fetched.ids, opts.Prune)
+			if err != nil {
+				return fmt.Errorf("上流から削除されたキャンペーンの削除に失敗しました: %w", err)
+			}
+		}
+
+		if err := uc.updateWatermark(ctx, fetched, opts); err != nil {
 			return err
 		}
+
+		return uc.saveCheckpoints(ctx, runID, []uint{fetched.accountID})
+	})
+	return saved, removed, err
+}
+
+// updateWatermark は取得したキャンペーンの更新日時の最大値で差分同期の基準日時を更新します
+// ステータスで絞り込んだ場合は保存していないキャンペーンを読み飛ばさないよう、基準日時を進めません
+// ただし --full と同時に指定した場合は、基準日時を取り直す代わりに削除し、次回は全件を取得します
+func (uc *CampaignUseCase) updateWatermark(ctx context.Context, fetched accountCampaigns, opts CampaignSyncOptions) error {
+	var err error
+	switch {
+	case len(opts.Statuses) > 0 && !opts.Full:
+		return nil
+	case len(opts.Statuses) > 0 || (fetched.watermark == nil && fetched.highWaterMark.IsZero()):
+		// 全件を取得したが更新日時がない場合は、次回も全件を取得する
+		err = uc.campaignRepo.DeleteWatermark(ctx, fetched.accountID, opts.source())
+	case fetched.watermark == nil || fetched.highWaterMark.After(*fetched.watermark):
+		err = uc.campaignRepo.SaveWatermark(ctx, fetched.accountID, opts.source(), fetched.highWaterMark)
+	default:
+		// 前回以降に更新されたキャンペーンがない場合は基準日時を変更しない
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("差分同期の基準日時の更新に失敗しました: %w", err)
 	}
+	return nil
+}
 
-	log.Info().Int("total_campaigns", len(allCampaigns)).Msg("キャンペーン情報の同期が完了しました")
+// saveCheckpoints は実行履歴のチェックポイントとして、キャンペーンの同期が完了したアカウントを記録します
+// 実行履歴を保存できなかった場合（runID が0の場合）は記録しません
+func (uc *CampaignUseCase) saveCheckpoints(ctx context.Context, runID uint, accountIDs []uint) error {
+	if runID == 0 {
+		return nil
+	}
+	if err := uc.runRepo.SaveCheckpoints(ctx, runID, accountIDs); err != nil {
+		return fmt.Errorf("チェックポイントの記録に失敗しました: %w", err)
+	}
 	return nil
 }
 
-// GetCampaignsByAccountID は指定されたアカウントIDに関連するキャンペーン情報を取得します
-func (uc *CampaignUseCase) GetCampaignsByAccountID(ctx context.Context, accountID uint) ([]entity.Campaign, error) {
-	return uc.campaignRepo.FindByAccountID(ctx, accountID)
+// GetCampaignsByAccountID は指定された取得元のアカウントに関連するキャンペーン情報を取得します
+func (uc *CampaignUseCase) GetCampaignsByAccountID(ctx context.Context, source string, accountID uint) ([]entity.Campaign, error) {
+	return uc.campaignRepo.FindByAccountID(ctx, source, accountID)
+}
+
+// GetCampaignHistory は指定された取得元のキャンペーンの変更履歴を版の古い順に取得します
+func (uc *CampaignUseCase) GetCampaignHistory(ctx context.Context, source string, campaignID uint) ([]entity.CampaignHistory, error) {
+	switch source {
+	case entity.SourceAPI1, entity.SourceAPI2:
+	default:
+		return nil, fmt.Errorf("未対応のキャンペーン取得元です: %s（%s, %s のいずれかを指定してください）", source, entity.SourceAPI1, entity.SourceAPI2)
+	}
+
+	histories, err := uc.campaignRepo.FindHistoryByCampaignID(ctx, source, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("キャンペーンの変更履歴の取得に失敗しました: %w", err)
+	}
+	if len(histories) == 0 {
+		return nil, fmt.Errorf("キャンペーンの変更履歴が見つかりません: %s %d", source, campaignID)
+	}
+	return histories, nil
+}
+
+// GetCampaignHistoryAt は指定された日時に有効だったキャンペーンの版を取得します
+func (uc *CampaignUseCase) GetCampaignHistoryAt(ctx context.Context, source string, campaignID uint, at time.Time) (*entity.CampaignHistory, error) {
+	histories, err := uc.GetCampaignHistory(ctx, source, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	for _, history := range histories {
+		if history.ValidAt(at) {
+			return &history, nil
+		}
+	}
+	return nil, fmt.Errorf("%s 時点で有効なキャンペーン %s %d の版はありません", model.FormatJST(at), source, campaignID)
+}
+
+// campaignFetcher は取得元に応じたキャンペーン取得用のリポジトリを返します
+func (uc *CampaignUseCase) campaignFetcher(source string) campaignFetcher {
+	if source == entity.SourceAPI2 {
+		return uc.campaignAPI2Repo
+	}
+	return uc.campaignAPIRepo
+}
+
+// findTargetAccounts は同期対象のアカウントを取得します
+// アカウントIDはキャンペーンの取得元と同じ取得元のアカウントのIDとして扱い、
+// 指定されていない場合は、キャンペーンの取得元と同じ取得元の全アカウントを返します
+func (uc *CampaignUseCase) findTargetAccounts(ctx context.Context, accountIDs []uint, source string) ([]entity.Account, error) {
+	if len(accountIDs) == 0 {
+		accounts, err := uc.accountRepo.FindAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return filterAccountsBySource(accounts, source), nil
+	}
+
+	accounts := make([]entity.Account, 0, len(accountIDs))
+	var notFound []string
+	for _, id := range accountIDs {
+		account, err := uc.accountRepo.FindByID(ctx, source, id)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			notFound = append(notFound, fmt.Sprint(id))
+			continue
+		}
+		accounts = append(accounts, *account)
+	}
+
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("指定されたアカウントが見つかりません: %s", strings.Join(notFound, ", "))
+	}
+
+	return accounts, nil
+}
+
+// filterCampaignsByStatus は指定されたステータスに一致するキャンペーンのみを返します
+// ステータスが指定されていない場合は全てのキャンペーンを返します
+func filterCampaignsByStatus(campaigns []entity.Campaign, statuses []string) []entity.Campaign {
+	if len(statuses) == 0 {
+		return campaigns
+	}
+
+	statusSet := make(map[string]struct{}, len(statuses))
+	for _, status := range statuses {
+		statusSet[strings.ToLower(status)] = struct{}{}
+	}
+
+	filtered := make([]entity.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if _, ok := statusSet[strings.ToLower(campaign.Status)]; ok {
+			filtered = append(filtered, campaign)
+		}
+	}
+	return filtered
+}
+
+// formatAccountIDs はアカウントIDを文字列のスライスに変換します
+func formatAccountIDs(accountIDs []uint) []string {
+	ids := make([]string, len(accountIDs))
+	for i, id := range accountIDs {
+		ids[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return ids
 }
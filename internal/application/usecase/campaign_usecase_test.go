@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func TestSyncCampaignsSaveFailure(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		opts            CampaignSyncOptions
+		wantErr         string
+		wantFetched     []uint // キャンペーンを取得したアカウントID
+		wantCheckpoints []uint
+	}{
+		{
+			name:            "失敗を許容するモードでは保存に失敗したアカウント以外の処理を継続する",
+			opts:            CampaignSyncOptions{Parallel: 1, ContinueOnError: true, FailureThreshold: 0.5},
+			wantFetched:     []uint{1, 2, 3, 4},
+			wantCheckpoints: []uint{1, 3, 4},
+		},
+		{
+			name:            "失敗率が閾値を超えた場合はエラー",
+			opts:            CampaignSyncOptions{Parallel: 1, ContinueOnError: true},
+			wantErr:         "1/4件（25%",
+			wantFetched:     []uint{1, 2, 3, 4},
+			wantCheckpoints: []uint{1, 3, 4},
+		},
+		{
+			name:            "失敗を許容しないモードでは処理を中止する",
+			opts:            CampaignSyncOptions{Parallel: 1},
+			wantErr:         "キャンペーンの保存に失敗しました",
+			wantFetched:     []uint{1, 2},
+			wantCheckpoints: []uint{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newFakeEnv(newTestAccounts(4)...)
+			for id := uint(1); id <= 4; id++ {
+				env.campaignAPI.campaigns[id] = []entity.Campaign{newTestCampaign(id, id*10, now)}
+			}
+			env.campaignRepo.saveErrs[2] = errors.New("キャンペーンの保存に失敗しました")
+
+			err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), tt.opts)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantFetched, env.campaignAPI.fetchedAccountIDs())
+
+			result := env.notifier.last()
+			require.NotNil(t, result)
+			assert.Equal(t, []string{"2"}, result.FailedAccountIDs)
+
+			checkpoints, err := env.runRepo.FindCheckpointAccountIDs(context.Background(), result.RunID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCheckpoints, checkpoints)
+		})
+	}
+}
+
+func TestSyncCampaignsFailureCancelsInFlightAccounts(t *testing.T) {
+	now := time.Now()
+	env := newFakeEnv(newTestAccounts(4)...)
+	for id := uint(1); id <= 4; id++ {
+		env.campaignAPI.campaigns[id] = []entity.Campaign{newTestCampaign(id, id*10, now)}
+	}
+	// アカウント1の取得は、アカウント2〜4の取得が始まってから失敗する
+	env.campaignAPI.errs[1] = errors.New("キャンペーンの取得に失敗しました")
+	env.campaignAPI.hold(2, 3, 4)
+
+	err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), CampaignSyncOptions{Parallel: 4})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "キャンペーンの取得に失敗しました")
+	assert.Equal(t, []uint{1, 2, 3, 4}, env.campaignAPI.fetchedAccountIDs())
+
+	// 失敗によって打ち切られたアカウントは失敗として数えない
+	result := env.notifier.last()
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"1"}, result.FailedAccountIDs)
+	assert.Equal(t, 1, result.ErrorCount)
+	assert.Equal(t, 0, result.SuccessCount)
+	assert.Equal(t, 1, result.TotalCount)
+}
+
+func TestSyncCampaignsParallel(t *testing.T) {
+	env := newFakeEnv(newTestAccounts(9)...)
+	env.campaignAPI.delay = 20 * time.Millisecond
+
+	err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), CampaignSyncOptions{Parallel: 3})
+	require.NoError(t, err)
+	assert.Len(t, env.campaignAPI.fetchedAccountIDs(), 9)
+
+	// 同時に取得するアカウント数は並列処理数を超えない
+	assert.LessOrEqual(t, env.campaignAPI.peak, 3)
+	assert.Greater(t, env.campaignAPI.peak, 1)
+}
+
+func TestSyncCampaignsInvalidParallel(t *testing.T) {
+	for _, parallel := range []int{0, -1, MaxParallel + 1} {
+		t.Run(strconv.Itoa(parallel), func(t *testing.T) {
+			env := newFakeEnv(newTestAccounts(2)...)
+
+			err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), CampaignSyncOptions{Parallel: parallel})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "並列処理数は1〜10の範囲で指定してください")
+			assert.Empty(t, env.campaignAPI.fetchedAccountIDs())
+
+			err = env.masterUseCase().SyncAll(context.Background(), MasterSyncOptions{Parallel: parallel})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "並列処理数は1〜10の範囲で指定してください")
+		})
+	}
+}
+
+func TestSyncCampaignsStatusFilter(t *testing.T) {
+	now := time.Now()
+	env := newFakeEnv(newTestAccounts(1)...)
+	active := newTestCampaign(1, 10, now)
+	paused := newTestCampaign(1, 11, now)
+	paused.Status = "PAUSED"
+	removed := newTestCampaign(1, 12, now)
+	removed.Status = "removed"
+	env.campaignAPI.campaigns[1] = []entity.Campaign{active, paused, removed}
+
+	// ステータスは大文字・小文字を区別せずに比較する
+	err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), CampaignSyncOptions{Parallel: 1, Statuses: []string{"Active", "paused"}})
+	require.NoError(t, err)
+
+	campaigns, err := env.campaignRepo.FindAll(context.Background())
+	require.NoError(t, err)
+	var ids []uint
+	for _, campaign := range campaigns {
+		ids = append(ids, campaign.ID)
+	}
+	assert.Equal(t, []uint{10, 11}, ids)
+	assert.Equal(t, 2, env.notifier.last().TotalRecords)
+}
+
+func TestSyncCampaignsUnknownAccountID(t *testing.T) {
+	env := newFakeEnv(newTestAccounts(2)...)
+
+	err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), CampaignSyncOptions{Parallel: 1, AccountIDs: []uint{1, 3, 4}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "指定されたアカウントが見つかりません: 3, 4")
+
+	// 見つからないアカウントがある場合は、どのアカウントのキャンペーンも取得しない
+	assert.Empty(t, env.campaignAPI.fetchedAccountIDs())
+}
+
+func TestSyncCampaignsWatermark(t *testing.T) {
+	base := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		watermark        *time.Time        // 記録済みの基準日時（nil の場合は記録なし）
+		upstream         []entity.Campaign // 上流のキャンペーン
+		opts             CampaignSyncOptions
+		wantUpdatedSince *time.Time // 外部APIに渡した updatedSince
+		wantWatermark    *time.Time // 同期後の基準日時（nil の場合は記録なし）
+		wantRemoval      bool       // 上流から削除されたキャンペーンを検出したかどうか
+	}{
+		{
+			name:          "基準日時がない場合は全件を取得して最大の更新日時を記録する",
+			upstream:      []entity.Campaign{newTestCampaign(1, 10, base.Add(time.Hour)), newTestCampaign(1, 11, base.Add(2*time.Hour))},
+			wantWatermark: timePtr(base.Add(2 * time.Hour)),
+			wantRemoval:   true,
+		},
+		{
+			name:             "差分取得で新しい更新がある場合は基準日時を進める",
+			watermark:        timePtr(base),
+			upstream:         []entity.Campaign{newTestCampaign(1, 10, base.Add(-time.Hour)), newTestCampaign(1, 11, base.Add(time.Hour))},
+			wantUpdatedSince: timePtr(base.Add(-WatermarkOverlap)),
+			wantWatermark:    timePtr(base.Add(time.Hour)),
+		},
+		{
+			name:             "差分取得で新しい更新がない場合は基準日時を変更しない",
+			watermark:        timePtr(base),
+			upstream:         []entity.Campaign{newTestCampaign(1, 10, base.Add(-time.Hour))},
+			wantUpdatedSince: timePtr(base.Add(-WatermarkOverlap)),
+			wantWatermark:    timePtr(base),
+		},
+		{
+			name:             "--status を指定した場合は基準日時を更新しない",
+			watermark:        timePtr(base),
+			upstream:         []entity.Campaign{newTestCampaign(1, 10, base.Add(time.Hour))},
+			opts:             CampaignSyncOptions{Statuses: []string{"paused"}},
+			wantUpdatedSince: timePtr(base.Add(-WatermarkOverlap)),
+			wantWatermark:    timePtr(base),
+		},
+		{
+			name:        "--full と --status を指定した場合は基準日時を削除する",
+			watermark:   timePtr(base),
+			upstream:    []entity.Campaign{newTestCampaign(1, 10, base.Add(time.Hour))},
+			opts:        CampaignSyncOptions{Full: true, Statuses: []string{"paused"}},
+			wantRemoval: true,
+		},
+		{
+			name:          "--full の場合は基準日時を使用せずに取り直す",
+			watermark:     timePtr(base),
+			upstream:      []entity.Campaign{newTestCampaign(1, 10, base.Add(-time.Hour))},
+			opts:          CampaignSyncOptions{Full: true},
+			wantWatermark: timePtr(base.Add(-time.Hour)),
+			wantRemoval:   true,
+		},
+		{
+			name:        "全件を取得して更新日時がない場合は基準日時を削除する",
+			watermark:   timePtr(base),
+			upstream:    []entity.Campaign{newTestCampaign(1, 10, time.Time{})},
+			opts:        CampaignSyncOptions{Full: true},
+			wantRemoval: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newFakeEnv(newTestAccounts(1)...)
+			env.campaignAPI.campaigns[1] = tt.upstream
+			if tt.watermark != nil {
+				require.NoError(t, env.campaignRepo.SaveWatermark(ctx, 1, entity.SourceAPI1, *tt.watermark))
+			}
+
+			opts := tt.opts
+			opts.Parallel = 1
+			require.NoError(t, env.campaignUseCase().SyncCampaignsWithOptions(ctx, opts))
+
+			assert.Equal(t, tt.wantUpdatedSince, env.campaignAPI.updatedSince[1])
+
+			watermarks, err := env.campaignRepo.FindWatermarks(ctx, entity.SourceAPI1)
+			require.NoError(t, err)
+			mark, ok := watermarks[1]
+			if tt.wantWatermark == nil {
+				assert.False(t, ok, "基準日時が残っています: %s", mark)
+			} else {
+				assert.True(t, ok)
+				assert.True(t, tt.wantWatermark.Equal(mark), "基準日時: %s（期待値: %s）", mark, tt.wantWatermark)
+			}
+
+			if tt.wantRemoval {
+				assert.Equal(t, []uint{1}, env.campaignRepo.removals)
+			} else {
+				assert.Empty(t, env.campaignRepo.removals)
+			}
+		})
+	}
+}
+
+func TestSyncCampaignsIncrementalKeepsUnchangedCampaigns(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	env := newFakeEnv(newTestAccounts(1)...)
+	env.campaignAPI.campaigns[1] = []entity.Campaign{
+		newTestCampaign(1, 10, base.Add(-time.Hour)),
+		newTestCampaign(1, 11, base.Add(time.Hour)),
+	}
+
+	// 1回目は全件を取得
+	require.NoError(t, env.campaignUseCase().SyncCampaignsWithOptions(ctx, CampaignSyncOptions{Parallel: 1}))
+
+	// 2回目は差分取得のため、更新されていないキャンペーン10は返らないが削除しない
+	env.campaignAPI.campaigns[1] = append(env.campaignAPI.campaigns[1], newTestCampaign(1, 12, base.Add(2*time.Hour)))
+	require.NoError(t, env.campaignUseCase().SyncCampaignsWithOptions(ctx, CampaignSyncOptions{Parallel: 1}))
+
+	campaigns, err := env.campaignRepo.FindByAccountID(ctx, entity.SourceAPI1, 1)
+	require.NoError(t, err)
+	ids := make([]uint, len(campaigns))
+	for i, campaign := range campaigns {
+		ids[i] = campaign.ID
+	}
+	assert.Equal(t, []uint{10, 11, 12}, ids)
+	assert.Equal(t, []uint{1}, env.campaignRepo.removals, "差分取得では削除を検出しない")
+}
+
+func TestSyncCampaignsIncrementalRefetchesWatermarkBoundary(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	env := newFakeEnv(newTestAccounts(1)...)
+	env.campaignAPI.campaigns[1] = []entity.Campaign{newTestCampaign(1, 10, base)}
+	require.NoError(t, env.campaignUseCase().SyncCampaignsWithOptions(ctx, CampaignSyncOptions{Parallel: 1}))
+
+	// 基準日時ちょうどに更新されたキャンペーンが前回の取得後に追加された場合でも、
+	// updatedSince の境界を含まない外部APIから取得できること
+	env.campaignAPI.campaigns[1] = append(env.campaignAPI.campaigns[1], newTestCampaign(1, 11, base))
+	require.NoError(t, env.campaignUseCase().SyncCampaignsWithOptions(ctx, CampaignSyncOptions{Parallel: 1}))
+
+	campaigns, err := env.campaignRepo.FindByAccountID(ctx, entity.SourceAPI1, 1)
+	require.NoError(t, err)
+	ids := make([]uint, len(campaigns))
+	for i, campaign := range campaigns {
+		ids[i] = campaign.ID
+	}
+	assert.Equal(t, []uint{10, 11}, ids)
+
+	// 再取得しても基準日時は巻き戻らないこと
+	watermarks, err := env.campaignRepo.FindWatermarks(ctx, entity.SourceAPI1)
+	require.NoError(t, err)
+	assert.True(t, base.Equal(watermarks[1]), "基準日時: %s", watermarks[1])
+}
+
+// timePtr は t へのポインタを返します
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
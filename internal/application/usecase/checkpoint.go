@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// findCompletedAccountIDs は再開する実行履歴を検証し、その実行でキャンペーンの同期が完了したアカウントのIDを返します
+// 取得元などのオプションが異なる実行を再開しないよう、コマンドが今回の実行と一致することを確認します
+func findCompletedAccountIDs(ctx context.Context, runRepo repository.SyncRunRepository, runID uint, process string) ([]uint, error) {
+	run, err := runRepo.FindByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("再開する実行履歴の取得に失敗しました: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("再開する実行履歴が見つかりません: %d", runID)
+	}
+	if run.Process != process {
+		return nil, fmt.Errorf("再開する実行履歴のコマンドが一致しません: %s（今回: %s）", run.Process, process)
+	}
+	if run.Status == model.StatusSuccess {
+		return nil, fmt.Errorf("実行履歴 %d は正常に完了しているため再開できません", runID)
+	}
+
+	accountIDs, err := runRepo.FindCheckpointAccountIDs(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("チェックポイントの取得に失敗しました: %w", err)
+	}
+	return accountIDs, nil
+}
+
+// skipCompletedAccounts は同期が完了したアカウントを除いたアカウントと、読み飛ばしたアカウントの件数を返します
+func skipCompletedAccounts(accounts []entity.Account, completedIDs []uint) ([]entity.Account, int) {
+	if len(completedIDs) == 0 {
+		return accounts, 0
+	}
+
+	completed := make(map[uint]struct{}, len(completedIDs))
+	for _, id := range completedIDs {
+		completed[id] = struct{}{}
+	}
+
+	remaining := make([]entity.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if _, ok := completed[account.ID]; ok {
+			continue
+		}
+		remaining = append(remaining, account)
+	}
+	return remaining, len(accounts) - len(remaining)
+}
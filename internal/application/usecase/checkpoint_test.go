@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+)
+
+func TestFindCompletedAccountIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     *entity.SyncRun
+		process string
+		want    []uint
+		wantErr string
+	}{
+		{
+			name:    "実行履歴が存在しない",
+			process: "campaign sync",
+			wantErr: "再開する実行履歴が見つかりません",
+		},
+		{
+			name:    "コマンドが一致しない",
+			run:     &entity.SyncRun{ID: 1, Process: "campaign sync --source api2", Status: model.StatusFailed},
+			process: "campaign sync",
+			wantErr: "再開する実行履歴のコマンドが一致しません",
+		},
+		{
+			name:    "オプションが一致しない",
+			run:     &entity.SyncRun{ID: 1, Process: "campaign sync", Status: model.StatusFailed},
+			process: "campaign sync --prune",
+			wantErr: "再開する実行履歴のコマンドが一致しません",
+		},
+		{
+			name:    "正常に完了した実行",
+			run:     &entity.SyncRun{ID: 1, Process: "campaign sync", Status: model.StatusSuccess},
+			process: "campaign sync",
+			wantErr: "正常に完了しているため再開できません",
+		},
+		{
+			name:    "失敗した実行",
+			run:     &entity.SyncRun{ID: 1, Process: "campaign sync", Status: model.StatusFailed},
+			process: "campaign sync",
+			want:    []uint{1, 2},
+		},
+		{
+			name:    "中断された実行",
+			run:     &entity.SyncRun{ID: 1, Process: "master sync --atomic", Status: model.StatusCancelled},
+			process: "master sync --atomic",
+			want:    []uint{1, 2},
+		},
+		{
+			name:    "異常終了して実行中のまま残った実行",
+			run:     &entity.SyncRun{ID: 1, Process: "campaign sync", Status: model.StatusRunning},
+			process: "campaign sync",
+			want:    []uint{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runRepo := newFakeRunRepository()
+			if tt.run != nil {
+				runRepo = newFakeRunRepository(*tt.run)
+				require.NoError(t, runRepo.SaveCheckpoints(context.Background(), tt.run.ID, []uint{2, 1}))
+			}
+
+			got, err := findCompletedAccountIDs(context.Background(), runRepo, 1, tt.process)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyncCampaignsResume(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		previous        entity.SyncRun
+		completedIDs    []uint // 再開する実行のチェックポイント
+		opts            CampaignSyncOptions
+		failAccountID   uint // キャンペーンの取得に失敗するアカウントID（0の場合は全て成功）
+		wantErr         string
+		wantFetched     []uint // キャンペーンを取得したアカウントID
+		wantCheckpoints []uint // 今回の実行のチェックポイント
+		wantStatus      string
+	}{
+		{
+			name:            "完了済みのアカウントを読み飛ばしてチェックポイントを引き継ぐ",
+			previous:        entity.SyncRun{ID: 1, Process: "campaign sync", Status: model.StatusFailed},
+			completedIDs:    []uint{1, 2},
+			opts:            CampaignSyncOptions{Parallel: 1},
+			wantFetched:     []uint{3, 4},
+			wantCheckpoints: []uint{1, 2, 3, 4},
+			wantStatus:      model.StatusSuccess,
+		},
+		{
+			name:            "再開した実行が途中で失敗した場合は完了したアカウントまで記録する",
+			previous:        entity.SyncRun{ID: 1, Process: "campaign sync --prune", Status: model.StatusCancelled},
+			completedIDs:    []uint{1},
+			opts:            CampaignSyncOptions{Parallel: 1, Prune: true},
+			failAccountID:   3,
+			wantErr:         "キャンペーンの取得に失敗しました",
+			wantFetched:     []uint{2, 3},
+			wantCheckpoints: []uint{1, 2},
+			wantStatus:      model.StatusFailed,
+		},
+		{
+			name:         "コマンドが一致しない実行は再開しない",
+			previous:     entity.SyncRun{ID: 1, Process: "campaign sync --source api2", Status: model.StatusFailed},
+			completedIDs: []uint{1, 2},
+			opts:         CampaignSyncOptions{Parallel: 1},
+			wantErr:      "再開する実行履歴のコマンドが一致しません",
+		},
+		{
+			name:         "正常に完了した実行は再開しない",
+			previous:     entity.SyncRun{ID: 1, Process: "campaign sync", Status: model.StatusSuccess},
+			completedIDs: []uint{1, 2},
+			opts:         CampaignSyncOptions{Parallel: 1},
+			wantErr:      "正常に完了しているため再開できません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newFakeEnv(newTestAccounts(4)...)
+			env.runRepo = newFakeRunRepository(tt.previous)
+			require.NoError(t, env.runRepo.SaveCheckpoints(context.Background(), tt.previous.ID, tt.completedIDs))
+			env.txManager.participants = []snapshotter{env.accountRepo, env.campaignRepo, env.runRepo}
+			for id := uint(1); id <= 4; id++ {
+				env.campaignAPI.campaigns[id] = []entity.Campaign{newTestCampaign(id, id*10, now)}
+			}
+			if tt.failAccountID != 0 {
+				env.campaignAPI.errs[tt.failAccountID] = errors.New("キャンペーンの取得に失敗しました")
+			}
+
+			opts := tt.opts
+			opts.ResumeRunID = tt.previous.ID
+			err := env.campaignUseCase().SyncCampaignsWithOptions(context.Background(), opts)
+
+			assert.Equal(t, tt.wantFetched, env.campaignAPI.fetchedAccountIDs())
+			if tt.wantStatus == "" {
+				// 再開を拒否した場合は実行履歴を作成しない
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Len(t, env.runRepo.runs, 1)
+				return
+			}
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			result := env.notifier.last()
+			require.NotNil(t, result)
+			assert.NotEqual(t, tt.previous.ID, result.RunID)
+			assert.Equal(t, tt.wantStatus, result.Status)
+
+			checkpoints, err := env.runRepo.FindCheckpointAccountIDs(context.Background(), result.RunID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCheckpoints, checkpoints)
+
+			// 再開する実行のチェックポイントは変更しない
+			previous, err := env.runRepo.FindCheckpointAccountIDs(context.Background(), tt.previous.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.completedIDs, previous)
+		})
+	}
+}
+
+func TestMasterSyncAtomicRollback(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		atomic          bool
+		wantCheckpoints []uint
+		wantCampaigns   int
+		wantAccounts    int
+	}{
+		{
+			name:            "--atomic の場合はチェックポイントを含めて全てロールバックする",
+			atomic:          true,
+			wantCheckpoints: []uint{},
+			wantCampaigns:   0,
+			wantAccounts:    3,
+		},
+		{
+			name:            "--atomic でない場合は完了したアカウントまで保存する",
+			atomic:          false,
+			wantCheckpoints: []uint{1},
+			wantCampaigns:   1,
+			wantAccounts:    4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 外部APIには新しいアカウントが追加されている
+			env := newFakeEnv(newTestAccounts(3)...)
+			env.accountAPI.accounts = newTestAccounts(4)
+			for id := uint(1); id <= 4; id++ {
+				env.campaignAPI.campaigns[id] = []entity.Campaign{newTestCampaign(id, id*10, now)}
+			}
+			env.campaignAPI.errs[2] = errors.New("キャンペーンの取得に失敗しました")
+
+			err := env.masterUseCase().SyncAll(context.Background(), MasterSyncOptions{Parallel: 1, Atomic: tt.atomic})
+			require.Error(t, err)
+
+			// ロールバックした場合も実行履歴は失敗として残す
+			result := env.notifier.last()
+			require.NotNil(t, result)
+			require.NotZero(t, result.RunID)
+			run, err := env.runRepo.FindByID(context.Background(), result.RunID)
+			require.NoError(t, err)
+			require.NotNil(t, run)
+			assert.Equal(t, model.StatusFailed, run.Status)
+
+			checkpoints, err := env.runRepo.FindCheckpointAccountIDs(context.Background(), result.RunID)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantCheckpoints, checkpoints)
+
+			campaigns, err := env.campaignRepo.FindAll(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, campaigns, tt.wantCampaigns)
+
+			accounts, err := env.accountRepo.FindAll(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, accounts, tt.wantAccounts)
+		})
+	}
+}
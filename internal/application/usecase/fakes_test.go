@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// テスト用のインメモリのリポジトリです
+// テストで使用するメソッドのみを実装し、それ以外のメソッドは埋め込んだ nil のインターフェースにより panic します
+
+// recordKey は取得元とIDの組み合わせです
+type recordKey struct {
+	source string
+	id     uint
+}
+
+// snapshotter はトランザクションのロールバックで元に戻す状態を持つインメモリのリポジトリです
+type snapshotter interface {
+	// snapshot は現在の状態を保存し、その状態に戻す関数を返します
+	snapshot() func()
+}
+
+// fakeTxManager はインメモリの TransactionManager です
+// fn がエラーを返した場合は、participants の状態を Do の開始時点に戻します
+// 入れ子で呼び出した場合も、セーブポイントと同様に内側の fn の書き込みのみを戻します
+type fakeTxManager struct {
+	participants []snapshotter
+}
+
+func (m *fakeTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	restores := make([]func(), len(m.participants))
+	for i, p := range m.participants {
+		restores[i] = p.snapshot()
+	}
+	if err := fn(ctx); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+	return nil
+}
+
+// fakeAccountRepository はインメモリの MySQLAccountRepository です
+type fakeAccountRepository struct {
+	repository.MySQLAccountRepository
+
+	mu       sync.Mutex
+	accounts map[recordKey]entity.Account
+}
+
+func newFakeAccountRepository(accounts ...entity.Account) *fakeAccountRepository {
+	r := &fakeAccountRepository{accounts: map[recordKey]entity.Account{}}
+	for _, account := range accounts {
+		r.accounts[recordKey{account.Source, account.ID}] = account
+	}
+	return r
+}
+
+func (r *fakeAccountRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := make(map[recordKey]entity.Account, len(r.accounts))
+	for k, v := range r.accounts {
+		saved[k] = v
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.accounts = saved
+	}
+}
+
+func (r *fakeAccountRepository) FindAll(_ context.Context) ([]entity.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accounts := make([]entity.Account, 0, len(r.accounts))
+	for _, account := range r.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts, nil
+}
+
+func (r *fakeAccountRepository) FindByID(_ context.Context, source string, id uint) (*entity.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	account, ok := r.accounts[recordKey{source, id}]
+	if !ok {
+		return nil, nil
+	}
+	return &account, nil
+}
+
+func (r *fakeAccountRepository) SaveAll(_ context.Context, accounts []entity.Account) (repository.UpsertResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result repository.UpsertResult
+	for _, account := range accounts {
+		key := recordKey{account.Source, account.ID}
+		if _, ok := r.accounts[key]; ok {
+			result.Updated++
+		} else {
+			result.Inserted++
+		}
+		r.accounts[key] = account
+	}
+	return result, nil
+}
+
+func (r *fakeAccountRepository) RemoveMissingBySource(_ context.Context, source string, keepIDs []uint, _ bool) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := 0
+	for key := range r.accounts {
+		if key.source == source && !containsID(keepIDs, key.id) {
+			delete(r.accounts, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// fakeCampaignRepository はインメモリの MySQLCampaignRepository です
+type fakeCampaignRepository struct {
+	repository.MySQLCampaignRepository
+
+	mu         sync.Mutex
+	campaigns  map[recordKey]entity.Campaign
+	watermarks map[string]map[uint]time.Time // 取得元ごと・アカウントIDごとの基準日時
+	removals   []uint                        // RemoveMissingByAccountID を呼び出したアカウントID
+	saveErrs   map[uint]error                // アカウントIDごとに SaveAll が返すエラー
+}
+
+func newFakeCampaignRepository() *fakeCampaignRepository {
+	return &fakeCampaignRepository{
+		campaigns:  map[recordKey]entity.Campaign{},
+		watermarks: map[string]map[uint]time.Time{},
+		saveErrs:   map[uint]error{},
+	}
+}
+
+func (r *fakeCampaignRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	campaigns := make(map[recordKey]entity.Campaign, len(r.campaigns))
+	for k, v := range r.campaigns {
+		campaigns[k] = v
+	}
+	watermarks := make(map[string]map[uint]time.Time, len(r.watermarks))
+	for source, marks := range r.watermarks {
+		watermarks[source] = make(map[uint]time.Time, len(marks))
+		for k, v := range marks {
+			watermarks[source][k] = v
+		}
+	}
+	removals := append([]uint(nil), r.removals...)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.campaigns = campaigns
+		r.watermarks = watermarks
+		r.removals = removals
+	}
+}
+
+func (r *fakeCampaignRepository) FindAll(_ context.Context) ([]entity.Campaign, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	campaigns := make([]entity.Campaign, 0, len(r.campaigns))
+	for _, campaign := range r.campaigns {
+		campaigns = append(campaigns, campaign)
+	}
+	sort.Slice(campaigns, func(i, j int) bool { return campaigns[i].ID < campaigns[j].ID })
+	return campaigns, nil
+}
+
+func (r *fakeCampaignRepository) FindByAccountID(ctx context.Context, source string, accountID uint) ([]entity.Campaign, error) {
+	campaigns, _ := r.FindAll(ctx)
+	filtered := make([]entity.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Source == source && campaign.AccountID == accountID {
+			filtered = append(filtered, campaign)
+		}
+	}
+	return filtered, nil
+}
+
+func (r *fakeCampaignRepository) SaveAll(_ context.Context, campaigns []entity.Campaign) (repository.UpsertResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result repository.UpsertResult
+	for _, campaign := range campaigns {
+		if err := r.saveErrs[campaign.AccountID]; err != nil {
+			return repository.UpsertResult{}, err
+		}
+	}
+	for _, campaign := range campaigns {
+		key := recordKey{campaign.Source, campaign.ID}
+		if _, ok := r.campaigns[key]; ok {
+			result.Updated++
+		} else {
+			result.Inserted++
+		}
+		r.campaigns[key] = campaign
+	}
+	return result, nil
+}
+
+func (r *fakeCampaignRepository) RemoveMissingByAccountID(_ context.Context, source string, accountID uint, keepIDs []uint, _ bool) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removals = append(r.removals, accountID)
+	removed := 0
+	for key, campaign := range r.campaigns {
+		if key.source == source && campaign.AccountID == accountID && !containsID(keepIDs, key.id) {
+			delete(r.campaigns, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (r *fakeCampaignRepository) FindWatermarks(_ context.Context, source string) (map[uint]time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	watermarks := map[uint]time.Time{}
+	for accountID, mark := range r.watermarks[source] {
+		watermarks[accountID] = mark
+	}
+	return watermarks, nil
+}
+
+func (r *fakeCampaignRepository) SaveWatermark(_ context.Context, accountID uint, source string, highWaterMark time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.watermarks[source] == nil {
+		r.watermarks[source] = map[uint]time.Time{}
+	}
+	r.watermarks[source][accountID] = highWaterMark
+	return nil
+}
+
+func (r *fakeCampaignRepository) DeleteWatermark(_ context.Context, accountID uint, source string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.watermarks[source], accountID)
+	return nil
+}
+
+// fakeRunRepository はインメモリの SyncRunRepository です
+// 実際のリポジトリと同様に、ロールバックではチェックポイントのみを元に戻し、実行履歴は残します
+type fakeRunRepository struct {
+	repository.SyncRunRepository
+
+	mu          sync.Mutex
+	runs        map[uint]entity.SyncRun
+	checkpoints map[uint][]uint
+}
+
+func newFakeRunRepository(runs ...entity.SyncRun) *fakeRunRepository {
+	r := &fakeRunRepository{runs: map[uint]entity.SyncRun{}, checkpoints: map[uint][]uint{}}
+	for _, run := range runs {
+		r.runs[run.ID] = run
+	}
+	return r
+}
+
+func (r *fakeRunRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := make(map[uint][]uint, len(r.checkpoints))
+	for k, v := range r.checkpoints {
+		saved[k] = append([]uint(nil), v...)
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.checkpoints = saved
+	}
+}
+
+func (r *fakeRunRepository) Create(_ context.Context, run *entity.SyncRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.ID = uint(len(r.runs) + 1)
+	for r.runs[run.ID].ID != 0 {
+		run.ID++
+	}
+	r.runs[run.ID] = *run
+	return nil
+}
+
+func (r *fakeRunRepository) Update(_ context.Context, run *entity.SyncRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.runs[run.ID]; !ok {
+		return fmt.Errorf("実行履歴が見つかりません: %d", run.ID)
+	}
+	r.runs[run.ID] = *run
+	return nil
+}
+
+func (r *fakeRunRepository) FindByID(_ context.Context, id uint) (*entity.SyncRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+func (r *fakeRunRepository) SaveCheckpoints(_ context.Context, runID uint, accountIDs []uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range accountIDs {
+		if !containsID(r.checkpoints[runID], id) {
+			r.checkpoints[runID] = append(r.checkpoints[runID], id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRunRepository) FindCheckpointAccountIDs(_ context.Context, runID uint) ([]uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := append([]uint(nil), r.checkpoints[runID]...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// fakeAccountFetcher は外部API1・外部API2のアカウント取得用のインメモリのリポジトリです
+type fakeAccountFetcher struct {
+	repository.ExternalAPI1AccountRepository
+
+	accounts []entity.Account
+}
+
+func (f *fakeAccountFetcher) StreamAccounts(_ context.Context, handler func(accounts []entity.Account) error) (int, error) {
+	return 1, handler(f.accounts)
+}
+
+// fakeCampaignFetcher は外部API1・外部API2のキャンペーン取得用のインメモリのリポジトリです
+// updatedSince を指定した場合は、その日時より後に更新されたキャンペーンのみを返します
+type fakeCampaignFetcher struct {
+	repository.ExternalAPI1CampaignRepository
+
+	mu           sync.Mutex
+	campaigns    map[uint][]entity.Campaign // アカウントIDごとのキャンペーン
+	errs         map[uint]error             // アカウントIDごとに返すエラー
+	fetched      []uint                     // キャンペーンを取得したアカウントID
+	updatedSince map[uint]*time.Time        // アカウントIDごとに指定された updatedSince
+
+	// held のアカウントはコンテキストがキャンセルされるまで取得を終えない
+	// errs のアカウントは held の全てのアカウントが取得を開始してからエラーを返す
+	held    map[uint]bool
+	holding sync.WaitGroup
+
+	// delay は1アカウントの取得にかかる時間です。active は取得中のアカウント数、peak はその最大値です
+	delay  time.Duration
+	active int
+	peak   int
+}
+
+func newFakeCampaignFetcher() *fakeCampaignFetcher {
+	return &fakeCampaignFetcher{
+		campaigns:    map[uint][]entity.Campaign{},
+		errs:         map[uint]error{},
+		updatedSince: map[uint]*time.Time{},
+	}
+}
+
+func (f *fakeCampaignFetcher) StreamCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time, handler func(campaigns []entity.Campaign) error) (int, error) {
+	// 実際のHTTPクライアントと同様に、キャンセルされたコンテキストではリクエストしない
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	f.mu.Lock()
+	f.active++
+	if f.active > f.peak {
+		f.peak = f.active
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.active--
+		f.mu.Unlock()
+	}()
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	f.fetched = append(f.fetched, accountID)
+	f.updatedSince[accountID] = updatedSince
+	err := f.errs[accountID]
+	var campaigns []entity.Campaign
+	for _, campaign := range f.campaigns[accountID] {
+		if updatedSince == nil || campaign.UpdatedAt.After(*updatedSince) {
+			campaigns = append(campaigns, campaign)
+		}
+	}
+	held := f.held[accountID]
+	f.mu.Unlock()
+
+	if held {
+		f.holding.Done()
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		f.holding.Wait()
+		return 0, err
+	}
+	return 1, handler(campaigns)
+}
+
+// hold は指定したアカウントの取得を、コンテキストがキャンセルされるまで終えないようにします
+func (f *fakeCampaignFetcher) hold(accountIDs ...uint) {
+	f.held = map[uint]bool{}
+	for _, id := range accountIDs {
+		f.held[id] = true
+	}
+	f.holding.Add(len(accountIDs))
+}
+
+// fetchedAccountIDs はキャンペーンを取得したアカウントIDを昇順で返します
+func (f *fakeCampaignFetcher) fetchedAccountIDs() []uint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := append([]uint(nil), f.fetched...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// fakeNotifier は通知したコマンド実行結果を記録する NotificationRepository です
+type fakeNotifier struct {
+	mu      sync.Mutex
+	results []*model.CommandResult
+}
+
+func (n *fakeNotifier) NotifyCommandResult(result *model.CommandResult) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.results = append(n.results, result)
+	return nil
+}
+
+func (n *fakeNotifier) LogCommandResult(_ *model.CommandResult) {}
+
+// last は最後に通知したコマンド実行結果を返します
+func (n *fakeNotifier) last() *model.CommandResult {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.results) == 0 {
+		return nil
+	}
+	return n.results[len(n.results)-1]
+}
+
+// fakeEnv はインメモリのリポジトリで組み立てたユースケースのテスト環境です
+type fakeEnv struct {
+	accountRepo  *fakeAccountRepository
+	campaignRepo *fakeCampaignRepository
+	runRepo      *fakeRunRepository
+	txManager    *fakeTxManager
+	accountAPI   *fakeAccountFetcher
+	accountAPI2  *fakeAccountFetcher
+	campaignAPI  *fakeCampaignFetcher
+	campaignAPI2 *fakeCampaignFetcher
+	notifier     *fakeNotifier
+}
+
+// newFakeEnv は accounts を保存済みのテスト環境を作成します
+// 外部API1のアカウント一覧は、保存済みのアカウントと同じ内容を返します
+func newFakeEnv(accounts ...entity.Account) *fakeEnv {
+	env := &fakeEnv{
+		accountRepo:  newFakeAccountRepository(accounts...),
+		campaignRepo: newFakeCampaignRepository(),
+		runRepo:      newFakeRunRepository(),
+		accountAPI:   &fakeAccountFetcher{accounts: accounts},
+		accountAPI2:  &fakeAccountFetcher{},
+		campaignAPI:  newFakeCampaignFetcher(),
+		campaignAPI2: newFakeCampaignFetcher(),
+		notifier:     &fakeNotifier{},
+	}
+	env.txManager = &fakeTxManager{participants: []snapshotter{env.accountRepo, env.campaignRepo, env.runRepo}}
+	return env
+}
+
+func (e *fakeEnv) accountUseCase() *AccountUseCase {
+	return NewAccountUseCase(e.accountRepo, e.accountAPI, e.accountAPI2, e.runRepo, e.notifier)
+}
+
+func (e *fakeEnv) campaignUseCase() *CampaignUseCase {
+	return NewCampaignUseCase(e.campaignRepo, e.campaignAPI, e.campaignAPI2, e.accountRepo, e.txManager, e.runRepo, e.notifier)
+}
+
+func (e *fakeEnv) masterUseCase() *MasterUseCase {
+	return NewMasterUseCase(e.accountUseCase(), e.campaignUseCase(), e.txManager, e.runRepo, e.notifier)
+}
+
+// newTestAccounts は ID が 1〜n の api1 のアカウントを作成します
+func newTestAccounts(n int) []entity.Account {
+	accounts := make([]entity.Account, n)
+	for i := range accounts {
+		accounts[i] = entity.Account{Source: entity.SourceAPI1, ID: uint(i + 1), Name: fmt.Sprintf("アカウント%d", i+1)}
+	}
+	return accounts
+}
+
+// newTestCampaign はアカウント accountID の api1 のキャンペーンを作成します
+func newTestCampaign(accountID, id uint, updatedAt time.Time) entity.Campaign {
+	return entity.Campaign{Source: entity.SourceAPI1, ID: id, AccountID: accountID, Status: "active", UpdatedAt: updatedAt}
+}
+
+// containsID は ids に id が含まれるかどうかを返します
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
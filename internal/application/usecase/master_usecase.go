@@ -4,37 +4,135 @@ import (
 	"context"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
 )
 
+// MasterSyncOptions はマスター同期のオプションです
+type MasterSyncOptions struct {
+	AccountIDs       []uint  // キャンペーン同期の対象とするアカウントID（空の場合は全アカウント）
+	Parallel         int     // キャンペーン同期の並列処理数（1-10）
+	ContinueOnError  bool    // キャンペーン同期で一部のアカウントが失敗しても処理を継続するかどうか
+	FailureThreshold float64 // キャンペーン同期で許容する失敗アカウントの割合（0.0〜1.0）
+	Prune            bool    // 上流から削除されたアカウント・キャンペーンを物理削除するかどうか
+
+	// Atomic が true の場合、アカウント同期とキャンペーン同期の書き込みを1つのトランザクションで実行し、
+	// どちらかが失敗した場合は両方をロールバックします
+	Atomic bool
+
+	// ResumeRunID は再開する実行履歴のIDです（0の場合は最初から同期します）
+	// アカウント同期は再度実行し、キャンペーン同期は指定した実行で完了したアカウントを読み飛ばします
+	ResumeRunID uint
+
+	// Full が true の場合、キャンペーン同期で差分同期の基準日時を使用せずに全てのキャンペーンを取得し、基準日時を取り直します
+	Full bool
+}
+
 // MasterUseCase はマスター同期関連のユースケースを実装します
 type MasterUseCase struct {
-	accountUseCase  *AccountUseCase
-	campaignUseCase *CampaignUseCase
+	accountUseCase   *AccountUseCase
+	campaignUseCase  *CampaignUseCase
+	txManager        repository.TransactionManager
+	runRepo          repository.SyncRunRepository
+	notificationRepo repository.NotificationRepository
 }
 
 // NewMasterUseCase は MasterUseCase の新しいインスタンスを作成します
 func NewMasterUseCase(
 	accountUseCase *AccountUseCase,
 	campaignUseCase *CampaignUseCase,
+	txManager repository.TransactionManager,
+	runRepo repository.SyncRunRepository,
+	notificationRepo repository.NotificationRepository,
 ) *MasterUseCase {
 	return &MasterUseCase{
-		accountUseCase:  accountUseCase,
-		campaignUseCase: campaignUseCase,
+		accountUseCase:   accountUseCase,
+		campaignUseCase:  campaignUseCase,
+		txManager:        txManager,
+		runRepo:          runRepo,
+		notificationRepo: notificationRepo,
 	}
 }
 
 // SyncAll はアカウント情報とキャンペーン情報を順番に同期します
-func (uc *MasterUseCase) SyncAll(ctx context.Context) error {
-	log.Info().Msg("マスター同期を開始します")
+// 両フェーズの処理結果は1つの CommandResult に集約して通知します
+func (uc *MasterUseCase) SyncAll(ctx context.Context, opts MasterSyncOptions) error {
+	campaignOpts := CampaignSyncOptions{
+		AccountIDs:       opts.AccountIDs,
+		Parallel:         opts.Parallel,
+		ContinueOnError:  opts.ContinueOnError,
+		FailureThreshold: opts.FailureThreshold,
+		Prune:            opts.Prune,
+		ResumeRunID:      opts.ResumeRunID,
+		Full:             opts.Full,
+	}
+
+	// アカウント同期を始める前にオプションを検証
+	if err := campaignOpts.Validate(); err != nil {
+		return err
+	}
+
+	log.Info().Uints("account_ids", opts.AccountIDs).Int("parallel", opts.Parallel).Bool("atomic", opts.Atomic).Uint("resume_run_id", opts.ResumeRunID).Msg("マスター同期を開始します")
+
+	process := "master sync"
+	if opts.Prune {
+		process += " --prune"
+	}
+	if opts.Atomic {
+		process += " --atomic"
+	}
+	if opts.Full {
+		process += " --full"
+	}
+
+	// 再開する実行でキャンペーンの同期が完了したアカウントを取得
+	var completedIDs []uint
+	if opts.ResumeRunID != 0 {
+		var err error
+		completedIDs, err = findCompletedAccountIDs(ctx, uc.runRepo, opts.ResumeRunID, process)
+		if err != nil {
+			return err
+		}
+	}
+
+	// コマンド実行結果の記録を開始
+	result := startCommandResult(ctx, uc.runRepo, process)
+
+	var err error
+	if opts.Atomic {
+		// アカウント同期とキャンペーン同期の書き込みを1つのトランザクションで実行
+		// チェックポイントも同じトランザクションで記録するため、ロールバックした場合は残らない
+		// --continue-on-error で読み飛ばしたアカウントの書き込みは、アカウントごとのセーブポイントまでロールバックされる
+		err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+			return uc.syncAll(ctx, campaignOpts, completedIDs, result)
+		})
+		if err != nil {
+			log.Warn().Msg("マスター同期に失敗したため、アカウント情報とキャンペーン情報の書き込みをロールバックしました")
+		}
+	} else {
+		err = uc.syncAll(ctx, campaignOpts, completedIDs, result)
+	}
+	if err != nil {
+		result.SetError(err)
+	}
+
+	// 実行履歴を保存し、通知を送信
+	recordCommandResult(ctx, uc.runRepo, uc.notificationRepo, result)
+	return err
+}
 
+// syncAll はアカウント同期とキャンペーン同期を順に実行し、処理結果を result に記録します
+// completedIDs に含まれるアカウントはキャンペーン同期を読み飛ばします
+func (uc *MasterUseCase) syncAll(ctx context.Context, campaignOpts CampaignSyncOptions, completedIDs []uint, result *model.CommandResult) error {
 	// アカウント情報の同期
-	if err := uc.accountUseCase.SyncAccounts(ctx); err != nil {
+	if err := uc.accountUseCase.syncAccounts(ctx, AccountSyncOptions{Mode: SyncModeFull, Prune: campaignOpts.Prune}, result); err != nil {
 		log.Error().Err(err).Msg("アカウント情報の同期に失敗しました")
 		return err
 	}
 
 	// キャンペーン情報の同期
-	if err := uc.campaignUseCase.SyncCampaigns(ctx); err != nil {
+	if err := uc.campaignUseCase.syncCampaigns(ctx, campaignOpts, completedIDs, result); err != nil {
 		log.Error().Err(err).Msg("キャンペーン情報の同期に失敗しました")
 		return err
 	}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func TestSyncAllFailureThreshold(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		failed    int // キャンペーンの取得に失敗するアカウント数（10アカウント中）
+		threshold float64
+		wantErr   bool
+	}{
+		// アカウント同期の件数を含めると失敗率は 5/20 = 25% になるが、キャンペーン同期の失敗率 50% で判定する
+		{name: "失敗率が閾値を超えた場合はエラー", failed: 5, threshold: 0.3, wantErr: true},
+		{name: "失敗率が閾値以下の場合は成功", failed: 5, threshold: 0.5, wantErr: false},
+		{name: "失敗がない場合は成功", failed: 0, threshold: 0, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newFakeEnv(newTestAccounts(10)...)
+			for id := uint(1); id <= 10; id++ {
+				env.campaignAPI.campaigns[id] = []entity.Campaign{newTestCampaign(id, id*10, now)}
+			}
+			for id := uint(1); id <= uint(tt.failed); id++ {
+				env.campaignAPI.errs[id] = errors.New("キャンペーンの取得に失敗しました")
+			}
+
+			err := env.masterUseCase().SyncAll(context.Background(), MasterSyncOptions{
+				Parallel:         2,
+				ContinueOnError:  true,
+				FailureThreshold: tt.threshold,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "5/10件（50%")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSyncAllAccountIDs(t *testing.T) {
+	env := newFakeEnv(newTestAccounts(3)...)
+	for id := uint(1); id <= 3; id++ {
+		env.campaignAPI.campaigns[id] = []entity.Campaign{newTestCampaign(id, id*10, time.Now())}
+	}
+
+	err := env.masterUseCase().SyncAll(context.Background(), MasterSyncOptions{AccountIDs: []uint{3, 1}, Parallel: 1})
+	require.NoError(t, err)
+
+	// アカウント情報は全件を同期し、キャンペーンは指定したアカウントのみ同期する
+	accounts, err := env.accountRepo.FindAll(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, accounts, 3)
+	assert.Equal(t, []uint{1, 3}, env.campaignAPI.fetchedAccountIDs())
+
+	result := env.notifier.last()
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"3", "1"}, result.AccountIDs)
+}
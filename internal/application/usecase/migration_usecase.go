@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// MigrationUseCase はスキーママイグレーション関連のユースケースを実装します
+type MigrationUseCase struct {
+	migrationRepo    repository.SchemaMigrationRepository
+	runRepo          repository.SyncRunRepository
+	notificationRepo repository.NotificationRepository
+}
+
+// NewMigrationUseCase は MigrationUseCase の新しいインスタンスを作成します
+func NewMigrationUseCase(
+	migrationRepo repository.SchemaMigrationRepository,
+	runRepo repository.SyncRunRepository,
+	notificationRepo repository.NotificationRepository,
+) *MigrationUseCase {
+	return &MigrationUseCase{
+		migrationRepo:    migrationRepo,
+		runRepo:          runRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Up は未適用のマイグレーションを適用します（steps が0の場合は全て）
+func (uc *MigrationUseCase) Up(ctx context.Context, steps int) ([]repository.SchemaMigration, error) {
+	if steps < 0 {
+		return nil, fmt.Errorf("適用するマイグレーションの件数は0以上を指定してください: %d", steps)
+	}
+
+	result := model.NewCommandResult("migrate up")
+	applied, err := uc.migrationRepo.Up(ctx, steps)
+	if err != nil {
+		result.SetError(err)
+	}
+	recordCommandResult(ctx, uc.runRepo, uc.notificationRepo, result)
+
+	log.Info().Int("applied", len(applied)).Msg("マイグレーションの適用が完了しました")
+	return applied, err
+}
+
+// Down は適用済みのマイグレーションを新しい順に steps 件取り消します
+func (uc *MigrationUseCase) Down(ctx context.Context, steps int) ([]repository.SchemaMigration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("取り消すマイグレーションの件数は1以上を指定してください: %d", steps)
+	}
+
+	result := model.NewCommandResult("migrate down")
+	reverted, err := uc.migrationRepo.Down(ctx, steps)
+	if err != nil {
+		result.SetError(err)
+	}
+	recordCommandResult(ctx, uc.runRepo, uc.notificationRepo, result)
+
+	log.Info().Int("reverted", len(reverted)).Msg("マイグレーションの取り消しが完了しました")
+	return reverted, err
+}
+
+// Status は全てのマイグレーションと適用状況を返します
+func (uc *MigrationUseCase) Status(ctx context.Context) ([]repository.SchemaMigration, error) {
+	return uc.migrationRepo.Status(ctx)
+}
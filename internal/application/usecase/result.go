@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// startCommandResult はコマンド実行結果の記録を開始し、実行中の実行履歴を保存します
+// 実行履歴のIDはチェックポイントの記録に使用します（保存に失敗した場合はIDを0のまま処理を継続します）
+func startCommandResult(ctx context.Context, runRepo repository.SyncRunRepository, process string) *model.CommandResult {
+	result := model.NewCommandResult(process)
+
+	run := newSyncRun(result)
+	run.Status = model.StatusRunning
+	if err := runRepo.Create(ctx, &run); err != nil {
+		log.Error().Err(err).Msg("実行履歴の保存に失敗しました")
+		return result
+	}
+	result.RunID = run.ID
+
+	log.Info().Uint("run_id", run.ID).Str("process", process).Msg("実行履歴の記録を開始しました")
+	return result
+}
+
+// recordCommandResult はコマンド実行結果の終了時刻を記録し、実行履歴の保存と通知の送信を行います
+// 実行履歴の保存と通知の失敗は処理結果に影響させないため、ログ出力のみ行います
+// ロールバックした場合も実行履歴を残すため、同期処理のトランザクションの外で呼び出します
+func recordCommandResult(ctx context.Context, runRepo repository.SyncRunRepository, notificationRepo repository.NotificationRepository, result *model.CommandResult) {
+	result.Complete()
+
+	// シグナルの受信などでコンテキストがキャンセルされて失敗した場合は、中断として記録する
+	// （タイムアウトの場合は失敗のまま記録する）
+	if !result.IsSuccess() && errors.Is(ctx.Err(), context.Canceled) {
+		result.SetCancelled()
+	}
+
+	// 処理が中断された場合も実行履歴を残すため、キャンセルされないコンテキストで保存する
+	run := newSyncRun(result)
+	if result.RunID != 0 {
+		// 実行開始時に保存した実行履歴を更新
+		run.ID = result.RunID
+		if err := runRepo.Update(context.WithoutCancel(ctx), &run); err != nil {
+			log.Error().Err(err).Msg("実行履歴の更新に失敗しました")
+		}
+	} else if err := runRepo.Create(context.WithoutCancel(ctx), &run); err != nil {
+		log.Error().Err(err).Msg("実行履歴の保存に失敗しました")
+	}
+
+	if err := notificationRepo.NotifyCommandResult(result); err != nil {
+		log.Error().Err(err).Msg("通知の送信に失敗しました")
+	}
+}
+
+// newSyncRun はコマンド実行結果から実行履歴を作成します
+func newSyncRun(result *model.CommandResult) entity.SyncRun {
+	return entity.SyncRun{
+		Process:          result.Process,
+		Status:           result.Status,
+		AccountIDs:       strings.Join(result.AccountIDs, ","),
+		DateFrom:         result.DateFrom,
+		DateTo:           result.DateTo,
+		StartedAt:        result.StartTime,
+		FinishedAt:       result.EndTime,
+		TotalCount:       result.TotalCount,
+		SuccessCount:     result.SuccessCount,
+		ErrorCount:       result.ErrorCount,
+		TotalRecords:     result.TotalRecords,
+		InsertedCount:    result.InsertedCount,
+		UpdatedCount:     result.UpdatedCount,
+		UnchangedCount:   result.UnchangedCount,
+		RemovedCount:     result.RemovedCount,
+		PageCount:        result.PageCount,
+		FailedAccountIDs: strings.Join(result.FailedAccountIDs, ","),
+		ErrorMessage:     result.ErrorMessage,
+	}
+}
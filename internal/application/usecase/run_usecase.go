@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// DefaultRunListLimit は実行履歴の一覧に表示する件数のデフォルト値です
+const DefaultRunListLimit = 20
+
+// RunUseCase はコマンドの実行履歴関連のユースケースを実装します
+type RunUseCase struct {
+	runRepo repository.SyncRunRepository
+}
+
+// NewRunUseCase は RunUseCase の新しいインスタンスを作成します
+func NewRunUseCase(runRepo repository.SyncRunRepository) *RunUseCase {
+	return &RunUseCase{
+		runRepo: runRepo,
+	}
+}
+
+// ListRuns は検索条件に一致する実行履歴を開始時刻の新しい順に取得します
+func (uc *RunUseCase) ListRuns(ctx context.Context, filter repository.SyncRunFilter) ([]entity.SyncRun, error) {
+	switch filter.Status {
+	case "", model.StatusSuccess, model.StatusFailed, model.StatusRunning, model.StatusCancelled:
+	default:
+		return nil, fmt.Errorf("不正なステータスです: %s（%s, %s, %s, %s のいずれかを指定してください）",
+			filter.Status, model.StatusSuccess, model.StatusFailed, model.StatusRunning, model.StatusCancelled)
+	}
+	if filter.Limit < 0 {
+		return nil, fmt.Errorf("表示件数は0以上を指定してください: %d", filter.Limit)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("期間の開始は終了より前を指定してください")
+	}
+
+	runs, err := uc.runRepo.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("実行履歴の取得に失敗しました: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun は指定されたIDの実行履歴を取得します
+func (uc *RunUseCase) GetRun(ctx context.Context, id uint) (*entity.SyncRun, error) {
+	run, err := uc.runRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("実行履歴の取得に失敗しました: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("実行履歴が見つかりません: %d", id)
+	}
+	return run, nil
+}
@@ -2,14 +2,21 @@ package entity
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Account はアカウント情報を表すエンティティです
+// 取得元ごとにアカウントIDの空間が異なるため、取得元とIDの組み合わせで識別します
 type Account struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
+	Source    string    `json:"source" gorm:"primaryKey;size:16"` // 取得元（api1 または api2）
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement:false"`
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
 	APIKey    string    `json:"api_key"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt は上流から削除されたことを検出して論理削除した日時です（削除されていない場合は NULL）
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
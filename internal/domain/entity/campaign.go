@@ -2,12 +2,16 @@ package entity
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Campaign はキャンペーン情報を表すエンティティです
+// 取得元ごとにキャンペーンIDの空間が異なるため、取得元とIDの組み合わせで識別します
 type Campaign struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	AccountID uint      `json:"account_id"`
+	Source    string    `json:"source" gorm:"primaryKey;size:16"` // 取得元（api1 または api2）
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	AccountID uint      `json:"account_id"` // 同じ取得元のアカウントのID
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
 	Budget    float64   `json:"budget"`
@@ -15,4 +19,7 @@ type Campaign struct {
 	EndDate   time.Time `json:"end_date"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt は上流から削除されたことを検出して論理削除した日時です（削除されていない場合は NULL）
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+)
+
+// CampaignHistory はキャンペーンの変更履歴（SCD Type 2）を表すエンティティです
+// ValidFrom から ValidTo までの期間にキャンペーンが保持していた値を版ごとに記録します
+type CampaignHistory struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Source     string     `json:"source" gorm:"uniqueIndex:idx_campaign_histories_source_campaign_version"` // キャンペーンの取得元
+	CampaignID uint       `json:"campaign_id" gorm:"uniqueIndex:idx_campaign_histories_source_campaign_version"`
+	Version    int        `json:"version" gorm:"uniqueIndex:idx_campaign_histories_source_campaign_version"` // キャンペーンごとの版番号（1からの連番）
+	AccountID  uint       `json:"account_id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Budget     float64    `json:"budget"`
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    time.Time  `json:"end_date"`
+	ValidFrom  time.Time  `json:"valid_from"` // この版の値が有効になった日時
+	ValidTo    *time.Time `json:"valid_to"`   // 次の版に置き換わった、または上流から削除された日時（現在の版は nil）
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewCampaignHistory はキャンペーンの現在の値から validFrom に始まる版を作成します
+func NewCampaignHistory(campaign Campaign, version int, validFrom time.Time) CampaignHistory {
+	return CampaignHistory{
+		Source:     campaign.Source,
+		CampaignID: campaign.ID,
+		Version:    version,
+		AccountID:  campaign.AccountID,
+		Name:       campaign.Name,
+		Status:     campaign.Status,
+		Budget:     campaign.Budget,
+		StartDate:  campaign.StartDate,
+		EndDate:    campaign.EndDate,
+		ValidFrom:  validFrom,
+	}
+}
+
+// IsCurrent は現在有効な版かどうかを返します
+func (h CampaignHistory) IsCurrent() bool {
+	return h.ValidTo == nil
+}
+
+// ValidAt は指定された日時にこの版の値が有効だったかどうかを返します
+func (h CampaignHistory) ValidAt(t time.Time) bool {
+	if t.Before(h.ValidFrom) {
+		return false
+	}
+	return h.ValidTo == nil || t.Before(*h.ValidTo)
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+)
+
+// CampaignSyncWatermark はアカウントごとのキャンペーンの差分同期の基準日時を表すエンティティです
+// 前回までに同期したキャンペーンの更新日時の最大値を記録し、次回はその日時以降に更新されたキャンペーンのみを取得します
+type CampaignSyncWatermark struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	AccountID     uint      `json:"account_id" gorm:"uniqueIndex:idx_campaign_sync_watermarks_account_source"`
+	Source        string    `json:"source" gorm:"size:16;uniqueIndex:idx_campaign_sync_watermarks_account_source"` // 取得元（api1 または api2）
+	HighWaterMark time.Time `json:"high_water_mark"`                                                               // 同期したキャンペーンの更新日時の最大値
+	UpdatedAt     time.Time `json:"updated_at"`
+}
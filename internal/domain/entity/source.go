@@ -0,0 +1,7 @@
+package entity
+
+// データの取得元
+const (
+	SourceAPI1 = "api1" // 外部API1
+	SourceAPI2 = "api2" // 外部API2（Google Ads）
+)
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+)
+
+// SyncRun はコマンドの実行履歴を表すエンティティです
+type SyncRun struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	Process          string    `json:"process" gorm:"index"` // 実行したコマンド（例: campaign sync --source api2）
+	Status           string    `json:"status" gorm:"index"`  // 処理結果のステータス（success/failed/running/cancelled）
+	AccountIDs       string    `json:"account_ids"`          // 処理対象のアカウントID（カンマ区切り）
+	DateFrom         string    `json:"date_from"`            // 処理対象期間（開始）
+	DateTo           string    `json:"date_to"`              // 処理対象期間（終了）
+	StartedAt        time.Time `json:"started_at" gorm:"index"`
+	FinishedAt       time.Time `json:"finished_at"`
+	TotalCount       int       `json:"total_count"`
+	SuccessCount     int       `json:"success_count"`
+	ErrorCount       int       `json:"error_count"`
+	TotalRecords     int       `json:"total_records"`
+	InsertedCount    int       `json:"inserted_count"`
+	UpdatedCount     int       `json:"updated_count"`
+	UnchangedCount   int       `json:"unchanged_count"`
+	RemovedCount     int       `json:"removed_count"`
+	PageCount        int       `json:"page_count"`
+	FailedAccountIDs string    `json:"failed_account_ids"` // 処理に失敗したアカウントID（カンマ区切り）
+	ErrorMessage     string    `json:"error_message"`      // 失敗時のエラーの概要
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Duration は処理時間を返します
+func (r SyncRun) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+)
+
+// SyncRunCheckpoint は実行中のコマンドでキャンペーンの同期が完了したアカウントを表すエンティティです
+// --resume で中断した実行を再開する際に、完了済みのアカウントを読み飛ばすために使用します
+type SyncRunCheckpoint struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	RunID       uint      `json:"run_id" gorm:"uniqueIndex:idx_sync_run_checkpoints_run_account"`
+	AccountID   uint      `json:"account_id" gorm:"uniqueIndex:idx_sync_run_checkpoints_run_account"`
+	CompletedAt time.Time `json:"completed_at"` // アカウントの同期が完了した日時
+}
@@ -6,19 +6,39 @@ import (
 	"time"
 )
 
+// コマンド実行結果のステータス
+const (
+	StatusSuccess   = "success"   // 成功
+	StatusFailed    = "failed"    // 失敗
+	StatusRunning   = "running"   // 実行中（プロセスが異常終了した場合はこのステータスのまま残る）
+	StatusCancelled = "cancelled" // 中断（SIGINT/SIGTERM などでキャンセルされた）
+)
+
 // CommandResult はコマンド実行結果を表します
 type CommandResult struct {
+	RunID        uint      // 実行履歴のID（実行開始時に実行履歴を保存していない場合は0）
 	Process      string    // 実行したコマンド
 	AccountIDs   []string  // 処理対象のアカウントID
 	DateFrom     string    // 処理対象期間（開始）
 	DateTo       string    // 処理対象期間（終了）
-	Status       string    // 処理結果のステータス（success/failed）
+	Status       string    // 処理結果のステータス（success/failed/cancelled）
 	StartTime    time.Time // 処理開始時刻
 	EndTime      time.Time // 処理終了時刻
 	TotalCount   int       // 処理したアカウントの件数
 	SuccessCount int       // 処理したアカウントの成功した件数
 	ErrorCount   int       // 処理したアカウントの失敗した件数
 	TotalRecords int       // 登録/更新したレコード数
+
+	InsertedCount  int // 新規登録したレコード数
+	UpdatedCount   int // 更新したレコード数
+	UnchangedCount int // 変更がなかったレコード数（差分同期時）
+	RemovedCount   int // 上流から削除されたため削除したレコード数
+
+	FailedAccountIDs []string // 処理に失敗したアカウントID
+
+	PageCount int // 外部APIから読み込んだページ数
+
+	ErrorMessage string // 失敗時のエラーの概要
 }
 
 // NewCommandResult はCommandResultの新しいインスタンスを作成します
@@ -26,7 +46,7 @@ func NewCommandResult(process string) *CommandResult {
 	return &CommandResult{
 		Process:   process,
 		StartTime: time.Now(),
-		Status:    "success", // デフォルトは成功
+		Status:    StatusSuccess, // デフォルトは成功
 	}
 }
 
@@ -48,7 +68,20 @@ func (r *CommandResult) Complete() {
 
 // SetFailed は処理を失敗としてマークします
 func (r *CommandResult) SetFailed() {
-	r.Status = "failed"
+	r.Status = StatusFailed
+}
+
+// SetCancelled は処理を中断としてマークします
+func (r *CommandResult) SetCancelled() {
+	r.Status = StatusCancelled
+}
+
+// SetError は処理を失敗としてマークし、エラーの概要を記録します
+func (r *CommandResult) SetError(err error) {
+	r.SetFailed()
+	if err != nil {
+		r.ErrorMessage = err.Error()
+	}
 }
 
 // AddCounts は処理結果のカウントを追加します
@@ -59,6 +92,33 @@ func (r *CommandResult) AddCounts(success, errorCount, records int) {
 	r.TotalRecords += records
 }
 
+// SetFailedAccountIDs は処理に失敗したアカウントIDを設定します
+func (r *CommandResult) SetFailedAccountIDs(accountIDs []string) {
+	r.FailedAccountIDs = accountIDs
+}
+
+// AddDiffCounts は差分同期の結果カウントを追加します
+func (r *CommandResult) AddDiffCounts(inserted, updated, unchanged int) {
+	r.InsertedCount += inserted
+	r.UpdatedCount += updated
+	r.UnchangedCount += unchanged
+}
+
+// HasDiffCounts は差分同期の結果カウントが記録されているかどうかを返します
+func (r *CommandResult) HasDiffCounts() bool {
+	return r.InsertedCount > 0 || r.UpdatedCount > 0 || r.UnchangedCount > 0
+}
+
+// AddRemovedCount は上流から削除されたため削除したレコード数を追加します
+func (r *CommandResult) AddRemovedCount(removed int) {
+	r.RemovedCount += removed
+}
+
+// AddPageCount は外部APIから読み込んだページ数を追加します
+func (r *CommandResult) AddPageCount(pages int) {
+	r.PageCount += pages
+}
+
 // FormatDuration は処理時間を人間が読みやすい形式でフォーマットします
 func (r *CommandResult) FormatDuration() string {
 	duration := r.EndTime.Sub(r.StartTime)
@@ -86,7 +146,12 @@ func (r *CommandResult) FormatDuration() string {
 
 // IsSuccess は処理が成功したかどうかを返します
 func (r *CommandResult) IsSuccess() bool {
-	return r.Status == "success"
+	return r.Status == StatusSuccess
+}
+
+// IsCancelled は処理が中断されたかどうかを返します
+func (r *CommandResult) IsCancelled() bool {
+	return r.Status == StatusCancelled
 }
 
 // FormatJST は時刻をJST形式でフォーマットします
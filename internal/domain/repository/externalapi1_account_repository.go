@@ -11,6 +11,10 @@ type ExternalAPI1AccountRepository interface {
 	// FetchAccounts は外部APIから全てのアカウント情報を取得します
 	FetchAccounts(ctx context.Context) ([]entity.Account, error)
 
+	// StreamAccounts は外部APIからアカウント情報をページ単位で取得し、ページごとに handler を呼び出します
+	// 読み込んだページ数を返します
+	StreamAccounts(ctx context.Context, handler func(accounts []entity.Account) error) (int, error)
+
 	// FetchAccountByID は外部APIから指定されたIDのアカウント情報を取得します
 	FetchAccountByID(ctx context.Context, id int) (entity.Account, error)
 }
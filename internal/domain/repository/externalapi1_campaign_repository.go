@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 )
@@ -9,5 +10,12 @@ import (
 // ExternalAPI1CampaignRepository は外部APIからキャンペーン情報を取得するリポジトリのインターフェースです
 type ExternalAPI1CampaignRepository interface {
 	// FetchCampaignsByAccountID は外部APIから指定されたアカウントIDに関連するキャンペーン情報を取得します
-	FetchCampaignsByAccountID(ctx context.Context, accountID uint) ([]entity.Campaign, error)
+	// updatedSince を指定した場合は、その日時以降に更新されたキャンペーンのみを取得します（nil の場合は全件）
+	// 外部API1で updatedSince ちょうどに更新されたキャンペーンが含まれるかどうかは保証されないため、
+	// 呼び出し側は取りこぼしを避けたい日時より前の日時を指定してください
+	FetchCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time) ([]entity.Campaign, error)
+
+	// StreamCampaignsByAccountID は外部APIから指定されたアカウントIDに関連するキャンペーン情報をページ単位で取得し、
+	// ページごとに handler を呼び出します。読み込んだページ数を返します
+	StreamCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time, handler func(campaigns []entity.Campaign) error) (int, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+// ExternalAPI2AccountRepository は外部API2（Google Ads）からアカウント情報を取得するリポジトリのインターフェースです
+type ExternalAPI2AccountRepository interface {
+	// FetchAccounts はマネージャーアカウント配下の全ての顧客アカウントを取得します
+	FetchAccounts(ctx context.Context) ([]entity.Account, error)
+
+	// StreamAccounts はマネージャーアカウント配下の顧客アカウントをページ単位で取得し、ページごとに handler を呼び出します
+	// 読み込んだページ数を返します
+	StreamAccounts(ctx context.Context, handler func(accounts []entity.Account) error) (int, error)
+}
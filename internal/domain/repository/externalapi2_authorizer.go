@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+)
+
+// ExternalAPI2AuthorizeOptions は外部API2（Google Ads）のOAuth2認可のオプションです
+type ExternalAPI2AuthorizeOptions struct {
+	Port         int              // リダイレクトを受け取るローカルポート（0の場合は空いているポート）
+	ClientID     string           // OAuth2クライアントID（空の場合は設定・Secret Managerの値）
+	ClientSecret string           // OAuth2クライアントシークレット（空の場合は設定・Secret Managerの値）
+	OnAuthURL    func(url string) // ブラウザで開く認可URLが決まったときに呼び出されます
+}
+
+// ExternalAPI2Credentials は認可によって得られた外部API2の認証情報です
+type ExternalAPI2Credentials struct {
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
+// ExternalAPI2Authorizer は外部API2（Google Ads）のOAuth2認可を行うリポジトリのインターフェースです
+type ExternalAPI2Authorizer interface {
+	// Authorize は認可コードフロー（ループバックリダイレクト、PKCE）を実行し、リフレッシュトークンを含む認証情報を返します
+	Authorize(ctx context.Context, opts ExternalAPI2AuthorizeOptions) (*ExternalAPI2Credentials, error)
+
+	// SaveCredentials は認証情報を設定されたシークレットに保存します
+	SaveCredentials(ctx context.Context, credentials *ExternalAPI2Credentials) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+// ExternalAPI2CampaignRepository は外部API2（Google Ads）からキャンペーン情報を取得するリポジトリのインターフェースです
+// アカウントIDは外部API2の顧客IDとして扱います
+type ExternalAPI2CampaignRepository interface {
+	// FetchCampaignsByAccountID は外部API2から指定されたアカウントIDに関連するキャンペーン情報を取得します
+	// 外部API2はキャンペーンの更新日時を返さないため、updatedSince に関わらず全件を取得します
+	FetchCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time) ([]entity.Campaign, error)
+
+	// StreamCampaignsByAccountID は外部API2から指定されたアカウントIDに関連するキャンペーン情報をページ単位で取得し、
+	// ページごとに handler を呼び出します。読み込んだページ数を返します
+	StreamCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time, handler func(campaigns []entity.Campaign) error) (int, error)
+}
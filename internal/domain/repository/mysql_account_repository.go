@@ -7,12 +7,13 @@ import (
 )
 
 // MySQLAccountRepository はアカウント情報の永続化を担当するリポジトリのインターフェースです
+// 取得元ごとにアカウントIDの空間が異なるため、IDは取得元と組み合わせて指定します
 type MySQLAccountRepository interface {
 	// FindAll は全てのアカウントを取得します
 	FindAll(ctx context.Context) ([]entity.Account, error)
 
-	// FindByID は指定されたIDのアカウントを取得します
-	FindByID(ctx context.Context, id uint) (*entity.Account, error)
+	// FindByID は指定された取得元とIDのアカウントを取得します
+	FindByID(ctx context.Context, source string, id uint) (*entity.Account, error)
 
 	// Create は新しいアカウントを作成します
 	Create(ctx context.Context, account *entity.Account) error
@@ -20,12 +21,17 @@ type MySQLAccountRepository interface {
 	// Update は既存のアカウントを更新します
 	Update(ctx context.Context, account *entity.Account) error
 
-	// Delete は指定されたIDのアカウントを削除します
-	Delete(ctx context.Context, id uint) error
+	// Delete は指定された取得元とIDのアカウントを削除します
+	Delete(ctx context.Context, source string, id uint) error
 
-	// SaveAll は複数のアカウントを一括で保存します
-	SaveAll(ctx context.Context, accounts []entity.Account) error
+	// SaveAll は複数のアカウントを一括で保存します（存在しない場合は作成、存在する場合は更新）
+	// 新規登録・更新したレコード数を返します
+	SaveAll(ctx context.Context, accounts []entity.Account) (UpsertResult, error)
 
 	// Save は単一のアカウントを保存します（存在しない場合は作成、存在する場合は更新）
 	Save(ctx context.Context, account entity.Account) error
+
+	// RemoveMissingBySource は指定された取得元のアカウントのうち、keepIDs に含まれないもの（上流から削除されたもの）を削除します
+	// prune が false の場合は論理削除（deleted_at を設定）、true の場合は物理削除し、削除したレコード数を返します
+	RemoveMissingBySource(ctx context.Context, source string, keepIDs []uint, prune bool) (int, error)
 }
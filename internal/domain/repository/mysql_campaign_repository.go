@@ -2,20 +2,22 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 )
 
 // MySQLCampaignRepository はキャンペーン情報の永続化を担当するリポジトリのインターフェースです
+// 取得元ごとにキャンペーンIDとアカウントIDの空間が異なるため、IDは取得元と組み合わせて指定します
 type MySQLCampaignRepository interface {
 	// FindAll は全てのキャンペーンを取得します
 	FindAll(ctx context.Context) ([]entity.Campaign, error)
 
-	// FindByID は指定されたIDのキャンペーンを取得します
-	FindByID(ctx context.Context, id uint) (*entity.Campaign, error)
+	// FindByID は指定された取得元とIDのキャンペーンを取得します
+	FindByID(ctx context.Context, source string, id uint) (*entity.Campaign, error)
 
-	// FindByAccountID は指定されたアカウントIDに関連するキャンペーンを全て取得します
-	FindByAccountID(ctx context.Context, accountID uint) ([]entity.Campaign, error)
+	// FindByAccountID は指定された取得元のアカウントに関連するキャンペーンを全て取得します
+	FindByAccountID(ctx context.Context, source string, accountID uint) ([]entity.Campaign, error)
 
 	// Create は新しいキャンペーンを作成します
 	Create(ctx context.Context, campaign *entity.Campaign) error
@@ -23,9 +25,26 @@ type MySQLCampaignRepository interface {
 	// Update は既存のキャンペーンを更新します
 	Update(ctx context.Context, campaign *entity.Campaign) error
 
-	// Delete は指定されたIDのキャンペーンを削除します
-	Delete(ctx context.Context, id uint) error
+	// Delete は指定された取得元とIDのキャンペーンを削除します
+	Delete(ctx context.Context, source string, id uint) error
 
-	// SaveAll は複数のキャンペーンを一括で保存します
-	SaveAll(ctx context.Context, campaigns []entity.Campaign) error
+	// SaveAll は複数のキャンペーンを一括で保存します（存在しない場合は作成、存在する場合は更新）
+	// 予算・ステータス・期間が変わったキャンペーンは変更履歴に新しい版を記録し、新規登録・更新したレコード数を返します
+	SaveAll(ctx context.Context, campaigns []entity.Campaign) (UpsertResult, error)
+
+	// RemoveMissingByAccountID は指定された取得元のアカウントのキャンペーンのうち、keepIDs に含まれないもの（上流から削除されたもの）を削除します
+	// prune が false の場合は論理削除（deleted_at を設定）、true の場合は物理削除し、削除したレコード数を返します
+	RemoveMissingByAccountID(ctx context.Context, source string, accountID uint, keepIDs []uint, prune bool) (int, error)
+
+	// FindHistoryByCampaignID は指定された取得元のキャンペーンの変更履歴を版の古い順に取得します
+	FindHistoryByCampaignID(ctx context.Context, source string, campaignID uint) ([]entity.CampaignHistory, error)
+
+	// FindWatermarks は指定された取得元の差分同期の基準日時をアカウントIDごとに取得します
+	FindWatermarks(ctx context.Context, source string) (map[uint]time.Time, error)
+
+	// SaveWatermark は指定されたアカウントの差分同期の基準日時を保存します（存在しない場合は作成、存在する場合は更新）
+	SaveWatermark(ctx context.Context, accountID uint, source string, highWaterMark time.Time) error
+
+	// DeleteWatermark は指定されたアカウントの差分同期の基準日時を削除します（次回は全件を取得します）
+	DeleteWatermark(ctx context.Context, accountID uint, source string) error
 }
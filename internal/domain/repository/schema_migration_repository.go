@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// SchemaMigration はバージョン管理されたスキーママイグレーションの状態を表します
+type SchemaMigration struct {
+	Version   uint       // バージョン（ファイル名の先頭の番号）
+	Name      string     // マイグレーション名
+	AppliedAt *time.Time // 適用日時（未適用の場合は nil）
+}
+
+// Applied はマイグレーションが適用済みかどうかを返します
+func (m SchemaMigration) Applied() bool {
+	return m.AppliedAt != nil
+}
+
+// SchemaMigrationRepository はデータベースのスキーママイグレーションを管理するリポジトリのインターフェースです
+type SchemaMigrationRepository interface {
+	// Up は未適用のマイグレーションを古い順に適用し、適用したマイグレーションを返します
+	// steps が0の場合は全ての未適用のマイグレーションを適用します
+	Up(ctx context.Context, steps int) ([]SchemaMigration, error)
+
+	// Down は適用済みのマイグレーションを新しい順に steps 件取り消し、取り消したマイグレーションを返します
+	Down(ctx context.Context, steps int) ([]SchemaMigration, error)
+
+	// Status は全てのマイグレーションと適用状況をバージョン順に返します
+	Status(ctx context.Context) ([]SchemaMigration, error)
+}
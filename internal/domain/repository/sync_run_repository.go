@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+// SyncRunFilter は実行履歴の検索条件です（ゼロ値の項目は絞り込みません）
+type SyncRunFilter struct {
+	Process string     // 実行したコマンド（前方一致）
+	Status  string     // 処理結果のステータス
+	From    *time.Time // 開始時刻の下限（この時刻を含む）
+	To      *time.Time // 開始時刻の上限（この時刻を含まない）
+	Limit   int        // 取得する件数の上限（0の場合は無制限）
+}
+
+// SyncRunRepository はコマンドの実行履歴の永続化を担当するリポジトリのインターフェースです
+type SyncRunRepository interface {
+	// Create は実行履歴を保存します
+	Create(ctx context.Context, run *entity.SyncRun) error
+
+	// Update は保存済みの実行履歴を更新します
+	Update(ctx context.Context, run *entity.SyncRun) error
+
+	// FindByID は指定されたIDの実行履歴を取得します（見つからない場合は nil）
+	FindByID(ctx context.Context, id uint) (*entity.SyncRun, error)
+
+	// Find は検索条件に一致する実行履歴を開始時刻の新しい順に取得します
+	Find(ctx context.Context, filter SyncRunFilter) ([]entity.SyncRun, error)
+
+	// SaveCheckpoints は指定された実行でキャンペーンの同期が完了したアカウントを記録します（記録済みのアカウントは無視します）
+	// コンテキストにトランザクションがある場合は、そのトランザクションで記録します
+	SaveCheckpoints(ctx context.Context, runID uint, accountIDs []uint) error
+
+	// FindCheckpointAccountIDs は指定された実行でキャンペーンの同期が完了したアカウントのIDを取得します
+	FindCheckpointAccountIDs(ctx context.Context, runID uint) ([]uint, error)
+}
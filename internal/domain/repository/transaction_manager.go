@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+)
+
+// TransactionManager は複数のリポジトリ操作を1つのトランザクションで実行するUnit of Workのインターフェースです
+type TransactionManager interface {
+	// Do は fn を1つのトランザクションで実行します
+	// fn に渡されたコンテキストを使用したリポジトリ操作は全て同じトランザクションで実行され、
+	// fn がエラーを返した場合はロールバック、nil を返した場合はコミットします
+	// トランザクション内で呼び出した場合は、fn の書き込みのみをロールバックできる入れ子のトランザクションとして実行します
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+// UpsertResult は一括保存（アップサート）の結果です
+type UpsertResult struct {
+	Inserted int // 新規登録したレコード数
+	Updated  int // 既に存在していたため更新したレコード数
+}
+
+// Add は別の一括保存の結果を加算します
+func (r *UpsertResult) Add(other UpsertResult) {
+	r.Inserted += other.Inserted
+	r.Updated += other.Updated
+}
+
+// Total は保存したレコード数を返します
+func (r UpsertResult) Total() int {
+	return r.Inserted + r.Updated
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+// AccountDiff は既存のアカウントと外部APIから取得したアカウントの差分を表します
+type AccountDiff struct {
+	Inserted  []entity.Account // 新規に追加されたアカウント
+	Updated   []entity.Account // 属性が変更されたアカウント
+	Unchanged []entity.Account // 変更がなかったアカウント
+}
+
+// Changed は書き込みが必要なアカウント（新規および変更）を返します
+func (d AccountDiff) Changed() []entity.Account {
+	changed := make([]entity.Account, 0, len(d.Inserted)+len(d.Updated))
+	changed = append(changed, d.Inserted...)
+	changed = append(changed, d.Updated...)
+	return changed
+}
+
+// DiffAccounts は既存のアカウントと外部APIから取得したアカウントを比較し、差分を検出します
+func DiffAccounts(existing, fetched []entity.Account) AccountDiff {
+	// 既存のアカウントをIDで引けるようにマップに格納
+	existingMap := make(map[uint]entity.Account, len(existing))
+	for _, account := range existing {
+		existingMap[account.ID] = account
+	}
+
+	var diff AccountDiff
+	for _, account := range fetched {
+		current, exists := existingMap[account.ID]
+		switch {
+		case !exists:
+			diff.Inserted = append(diff.Inserted, account)
+		case accountChanged(current, account):
+			diff.Updated = append(diff.Updated, account)
+		default:
+			diff.Unchanged = append(diff.Unchanged, account)
+		}
+	}
+
+	return diff
+}
+
+// accountChanged は同期対象の属性に変更があるかどうかを判定します
+// CreatedAt/UpdatedAt は永続化時に更新されるため比較対象に含めません
+func accountChanged(current, fetched entity.Account) bool {
+	return current.Source != fetched.Source ||
+		current.Name != fetched.Name ||
+		current.Status != fetched.Status ||
+		current.APIKey != fetched.APIKey
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func TestDiffAccounts(t *testing.T) {
+	now := time.Now()
+
+	// 既存のアカウント
+	existing := []entity.Account{
+		{ID: 1, Name: "アカウント1", Status: "active", APIKey: "key1", UpdatedAt: now.Add(-time.Hour)},
+		{ID: 2, Name: "アカウント2", Status: "active", APIKey: "key2", UpdatedAt: now.Add(-time.Hour)},
+		{ID: 3, Name: "アカウント3", Status: "inactive", APIKey: "key3"},
+	}
+
+	// 外部APIから取得したアカウント（タイムスタンプのみ異なるものは変更なしとみなす）
+	fetched := []entity.Account{
+		{ID: 1, Name: "アカウント1", Status: "active", APIKey: "key1", UpdatedAt: now},
+		{ID: 2, Name: "アカウント2", Status: "inactive", APIKey: "key2", UpdatedAt: now},
+		{ID: 4, Name: "アカウント4", Status: "active", APIKey: "key4", UpdatedAt: now},
+	}
+
+	diff := DiffAccounts(existing, fetched)
+
+	assert.Len(t, diff.Inserted, 1)
+	assert.Equal(t, uint(4), diff.Inserted[0].ID)
+	assert.Len(t, diff.Updated, 1)
+	assert.Equal(t, uint(2), diff.Updated[0].ID)
+	assert.Len(t, diff.Unchanged, 1)
+	assert.Equal(t, uint(1), diff.Unchanged[0].ID)
+
+	// 書き込み対象は新規と変更のみ
+	changed := diff.Changed()
+	assert.Len(t, changed, 2)
+}
+
+func TestDiffAccountsEmpty(t *testing.T) {
+	diff := DiffAccounts(nil, nil)
+
+	assert.Empty(t, diff.Inserted)
+	assert.Empty(t, diff.Updated)
+	assert.Empty(t, diff.Unchanged)
+	assert.Empty(t, diff.Changed())
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"time"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+// CampaignHistoryChanges は同期によって発生したキャンペーン履歴の変更を表します
+type CampaignHistoryChanges struct {
+	Closed []entity.CampaignHistory // 終了する現在の版（ValidTo を設定済み）
+	Opened []entity.CampaignHistory // 新しく開始する版
+}
+
+// TrackCampaignHistory はキャンペーンごとの最新の版と同期したキャンペーンを比較し、履歴の変更を検出します
+// 追跡対象の属性が変わった場合は現在の版を now で終了して新しい版を開始し、
+// 履歴がない、または最新の版が終了している（上流から削除されていた）場合は新しい版を開始します
+func TrackCampaignHistory(latest []entity.CampaignHistory, campaigns []entity.Campaign, now time.Time) CampaignHistoryChanges {
+	// 最新の版を取得元とキャンペーンIDで引けるようにマップに格納（取得元ごとにIDの空間が異なるため）
+	type campaignKey struct {
+		source string
+		id     uint
+	}
+	latestMap := make(map[campaignKey]entity.CampaignHistory, len(latest))
+	for _, history := range latest {
+		latestMap[campaignKey{history.Source, history.CampaignID}] = history
+	}
+
+	var changes CampaignHistoryChanges
+	for _, campaign := range campaigns {
+		current, exists := latestMap[campaignKey{campaign.Source, campaign.ID}]
+		switch {
+		case !exists:
+			changes.Opened = append(changes.Opened, entity.NewCampaignHistory(campaign, 1, now))
+		case !current.IsCurrent():
+			changes.Opened = append(changes.Opened, entity.NewCampaignHistory(campaign, current.Version+1, now))
+		case campaignHistoryChanged(current, campaign):
+			validTo := now
+			current.ValidTo = &validTo
+			changes.Closed = append(changes.Closed, current)
+			changes.Opened = append(changes.Opened, entity.NewCampaignHistory(campaign, current.Version+1, now))
+		}
+	}
+
+	return changes
+}
+
+// campaignHistoryChanged は履歴の追跡対象の属性（予算・ステータス・開始日・終了日）に変更があるかどうかを判定します
+// データベースの日時はミリ秒精度で保存されるため、ミリ秒未満の差は無視します
+func campaignHistoryChanged(current entity.CampaignHistory, campaign entity.Campaign) bool {
+	return current.Budget != campaign.Budget ||
+		current.Status != campaign.Status ||
+		!sameMillisecond(current.StartDate, campaign.StartDate) ||
+		!sameMillisecond(current.EndDate, campaign.EndDate)
+}
+
+// sameMillisecond は2つの日時がミリ秒単位で等しいかどうかを返します
+func sameMillisecond(a, b time.Time) bool {
+	return a.Truncate(time.Millisecond).Equal(b.Truncate(time.Millisecond))
+}
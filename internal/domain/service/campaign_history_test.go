@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func TestTrackCampaignHistory(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	removedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// キャンペーンごとの最新の版
+	latest := []entity.CampaignHistory{
+		{ID: 10, CampaignID: 1, Version: 1, Budget: 1000, Status: "active", StartDate: start, ValidFrom: start},
+		{ID: 20, CampaignID: 2, Version: 2, Budget: 2000, Status: "active", StartDate: start, ValidFrom: start},
+		{ID: 30, CampaignID: 3, Version: 1, Budget: 3000, Status: "active", ValidFrom: start, ValidTo: &removedAt},
+	}
+
+	campaigns := []entity.Campaign{
+		// 名前のみの変更は履歴に記録しない
+		{ID: 1, Name: "名前変更", Budget: 1000, Status: "active", StartDate: start.Add(time.Microsecond)},
+		// 予算が変更された
+		{ID: 2, Budget: 2500, Status: "active", StartDate: start},
+		// 上流から削除されていたキャンペーンが再び現れた
+		{ID: 3, Budget: 3000, Status: "active"},
+		// 新しいキャンペーン
+		{ID: 4, Budget: 4000, Status: "paused"},
+	}
+
+	changes := TrackCampaignHistory(latest, campaigns, now)
+
+	assert.Len(t, changes.Closed, 1)
+	assert.Equal(t, uint(20), changes.Closed[0].ID)
+	assert.True(t, now.Equal(*changes.Closed[0].ValidTo))
+
+	assert.Len(t, changes.Opened, 3)
+	assert.Equal(t, uint(2), changes.Opened[0].CampaignID)
+	assert.Equal(t, 3, changes.Opened[0].Version)
+	assert.Equal(t, 2500.0, changes.Opened[0].Budget)
+	assert.Equal(t, 2, changes.Opened[1].Version)
+	assert.Equal(t, uint(4), changes.Opened[2].CampaignID)
+	assert.Equal(t, 1, changes.Opened[2].Version)
+	assert.True(t, changes.Opened[2].IsCurrent())
+}
+
+func TestCampaignHistoryValidAt(t *testing.T) {
+	from := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	history := entity.CampaignHistory{ValidFrom: from, ValidTo: &to}
+
+	assert.False(t, history.ValidAt(from.Add(-time.Second)))
+	assert.True(t, history.ValidAt(from))
+	assert.False(t, history.ValidAt(to))
+
+	history.ValidTo = nil
+	assert.True(t, history.ValidAt(to))
+}
@@ -2,7 +2,6 @@ package externalapi1
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -12,131 +11,110 @@ import (
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
-	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/secrets"
 )
 
 // AccountRepositoryImpl はExternalAPI1AccountRepositoryインターフェースの実装です
 type AccountRepositoryImpl struct {
-	client    *http.Client
 	baseURL   string
 	mock      bool
 	apiClient *APIClient
+	paginator *paginator
 }
 
-// NewAccountRepository は新しいAccountRepositoryImplインスタンスを作成します
-func NewAccountRepository(cfg *config.Config, httpClient *http.Client, secretsManager secrets.Manager) repository.ExternalAPI1AccountRepository {
-	apiClient := NewAPIClient(cfg, httpClient, secretsManager)
-
-	return &AccountRepositoryImpl{
-		client:    httpClient,
-		baseURL:   cfg.ExternalAPI1.BaseURL,
-		mock:      true, // 常にモックを使用
-		apiClient: apiClient,
-	}
-}
-
-// FetchAccounts は外部APIからアカウント情報を取得します
-func (r *AccountRepositoryImpl) FetchAccounts(ctx context.Context) ([]entity.Account, error) {
-	if r.mock {
-		return r.fetchMockAccounts(ctx)
-	}
-
-	// 実際のAPIリクエストを行う場合の実装
-	url := fmt.Sprintf("%s%s", r.baseURL, "/api/accounts")
-
-	// 認証情報を含むリクエストを作成
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// NewAccountRepository は設定されたデータ取得元（mock, live, fixture）に応じたExternalAPI1AccountRepositoryを作成します
+func NewAccountRepository(cfg *config.Config, apiClient *APIClient) (repository.ExternalAPI1AccountRepository, error) {
+	source, err := resolveSource(cfg.ExternalAPI1.Source)
 	if err != nil {
-		log.Error().Err(err).Msg("リクエストの作成に失敗しました")
 		return nil, err
 	}
 
-	// トークンを取得して設定
-	headerName, headerValue, err := r.apiClient.GetAuthorizationHeader(ctx)
-	if err != nil {
-		return nil, err
-	}
+	log.Debug().Str("source", source).Msg("ExternalAPI1アカウントリポジトリのデータ取得元を設定しました")
 
-	if headerName != "" && headerValue != "" {
-		req.Header.Set(headerName, headerValue)
+	if source == SourceFixture {
+		return NewFixtureAccountRepository(cfg.ExternalAPI1.FixtureDir), nil
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// リクエストを送信
-	resp, err := r.client.Do(req)
+	paginator, err := newPaginator(cfg.ExternalAPI1.Pagination)
 	if err != nil {
-		log.Error().Err(err).Msg("APIリクエストの送信に失敗しました")
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// レスポンスのステータスコードを確認
-	if resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf("APIリクエストが失敗しました: ステータスコード %d", resp.StatusCode)
-		log.Error().Err(err).Int("status_code", resp.StatusCode).Msg("APIリクエストが失敗しました")
-		return nil, err
-	}
+	return &AccountRepositoryImpl{
+		baseURL:   cfg.ExternalAPI1.BaseURL,
+		mock:      source == SourceMock,
+		apiClient: apiClient,
+		paginator: paginator,
+	}, nil
+}
 
-	// レスポンスをデコード
+// FetchAccounts は外部APIから全てのアカウント情報を取得します
+func (r *AccountRepositoryImpl) FetchAccounts(ctx context.Context) ([]entity.Account, error) {
 	var accounts []entity.Account
-	if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
-		log.Error().Err(err).Msg("APIレスポンスのデコードに失敗しました")
+	if _, err := r.StreamAccounts(ctx, func(page []entity.Account) error {
+		accounts = append(accounts, page...)
+		return nil
+	}); err != nil {
 		return nil, err
 	}
 
 	return accounts, nil
 }
 
-// FetchAccountByID は外部APIから指定されたIDのアカウント情報を取得します
-func (r *AccountRepositoryImpl) FetchAccountByID(ctx context.Context, id int) (entity.Account, error) {
+// StreamAccounts は外部APIからアカウント情報をページ単位で取得し、ページごとに handler を呼び出します
+func (r *AccountRepositoryImpl) StreamAccounts(ctx context.Context, handler func(accounts []entity.Account) error) (int, error) {
+	handler = withAccountSource(handler)
+
 	if r.mock {
-		return r.fetchMockAccountByID(ctx, id)
+		accounts, err := r.fetchMockAccounts(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return 1, handler(accounts)
 	}
 
 	// 実際のAPIリクエストを行う場合の実装
-	url := fmt.Sprintf("%s%s/%d", r.baseURL, "/api/accounts", id)
+	url := fmt.Sprintf("%s%s", r.baseURL, "/api/accounts")
 
-	// 認証情報を含むリクエストを作成
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	pages, err := fetchPages(ctx, r.paginator, url, r.apiClient.getJSON, handler)
 	if err != nil {
-		log.Error().Err(err).Int("id", id).Msg("リクエストの作成に失敗しました")
-		return entity.Account{}, err
+		log.Error().Err(err).Str("url", url).Int("pages", pages).Msg("アカウント情報の取得に失敗しました")
+		return pages, err
 	}
 
-	// トークンを取得して設定
-	headerName, headerValue, err := r.apiClient.GetAuthorizationHeader(ctx)
-	if err != nil {
-		return entity.Account{}, err
-	}
+	log.Debug().Int("pages", pages).Msg("アカウント情報の全ページを読み込みました")
+	return pages, nil
+}
 
-	if headerName != "" && headerValue != "" {
-		req.Header.Set(headerName, headerValue)
+// FetchAccountByID は外部APIから指定されたIDのアカウント情報を取得します
+func (r *AccountRepositoryImpl) FetchAccountByID(ctx context.Context, id int) (entity.Account, error) {
+	if r.mock {
+		account, err := r.fetchMockAccountByID(ctx, id)
+		if err != nil {
+			return entity.Account{}, err
+		}
+		account.Source = entity.SourceAPI1
+		return account, nil
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// リクエストを送信
-	resp, err := r.client.Do(req)
+	// 実際のAPIリクエストを行う場合の実装
+	account, err := DoJSON[entity.Account](ctx, r.apiClient.NewRequest(http.MethodGet, fmt.Sprintf("/api/accounts/%d", id)))
 	if err != nil {
-		log.Error().Err(err).Int("id", id).Msg("APIリクエストの送信に失敗しました")
+		log.Error().Err(err).Int("id", id).Msg("アカウント情報の取得に失敗しました")
 		return entity.Account{}, err
 	}
-	defer resp.Body.Close()
 
-	// レスポンスのステータスコードを確認
-	if resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf("APIリクエストが失敗しました: ステータスコード %d", resp.StatusCode)
-		log.Error().Err(err).Int("status_code", resp.StatusCode).Int("id", id).Msg("APIリクエストが失敗しました")
-		return entity.Account{}, err
-	}
+	account.Source = entity.SourceAPI1
+	return account, nil
+}
 
-	// レスポンスをデコード
-	var account entity.Account
-	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
-		log.Error().Err(err).Int("id", id).Msg("APIレスポンスのデコードに失敗しました")
-		return entity.Account{}, err
+// withAccountSource は取得したアカウントに取得元（api1）を設定してから handler を呼び出します
+func withAccountSource(handler func(accounts []entity.Account) error) func(accounts []entity.Account) error {
+	return func(accounts []entity.Account) error {
+		for i := range accounts {
+			accounts[i].Source = entity.SourceAPI1
+		}
+		return handler(accounts)
 	}
-
-	return account, nil
 }
 
 // fetchMockAccounts はモックのアカウント情報を返します
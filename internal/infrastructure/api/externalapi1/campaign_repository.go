@@ -3,10 +3,9 @@ package externalapi1
 import (
 	"context"
 	"crypto/rand"
-	"encoding/json"
 	"fmt"
 	"math/big"
-	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -14,63 +13,113 @@ import (
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
-	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/secrets"
 )
 
 // CampaignRepositoryImpl はExternalAPI1CampaignRepositoryインターフェースの実装です
 type CampaignRepositoryImpl struct {
-	client    *http.Client
 	baseURL   string
 	mock      bool
 	apiClient *APIClient
+	paginator *paginator
 }
 
-// NewCampaignRepository は新しいCampaignRepositoryImplインスタンスを作成します
-func NewCampaignRepository(cfg *config.Config, httpClient *http.Client, secretsManager secrets.Manager) repository.ExternalAPI1CampaignRepository {
-	apiClient := NewAPIClient(cfg, httpClient, secretsManager)
+// NewCampaignRepository は設定されたデータ取得元（mock, live, fixture）に応じたExternalAPI1CampaignRepositoryを作成します
+func NewCampaignRepository(cfg *config.Config, apiClient *APIClient) (repository.ExternalAPI1CampaignRepository, error) {
+	source, err := resolveSource(cfg.ExternalAPI1.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("source", source).Msg("ExternalAPI1キャンペーンリポジトリのデータ取得元を設定しました")
+
+	if source == SourceFixture {
+		return NewFixtureCampaignRepository(cfg.ExternalAPI1.FixtureDir), nil
+	}
+
+	paginator, err := newPaginator(cfg.ExternalAPI1.Pagination)
+	if err != nil {
+		return nil, err
+	}
 
 	return &CampaignRepositoryImpl{
-		client:    httpClient,
 		baseURL:   cfg.ExternalAPI1.BaseURL,
-		mock:      true, // 常にモックを使用
+		mock:      source == SourceMock,
 		apiClient: apiClient,
-	}
+		paginator: paginator,
+	}, nil
 }
 
 // FetchCampaignsByAccountID は外部APIから指定されたアカウントIDに関連するキャンペーン情報を取得します
-func (r *CampaignRepositoryImpl) FetchCampaignsByAccountID(ctx context.Context, accountID uint) ([]entity.Campaign, error) {
+func (r *CampaignRepositoryImpl) FetchCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time) ([]entity.Campaign, error) {
+	var campaigns []entity.Campaign
+	if _, err := r.StreamCampaignsByAccountID(ctx, accountID, updatedSince, func(page []entity.Campaign) error {
+		campaigns = append(campaigns, page...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// StreamCampaignsByAccountID は外部APIから指定されたアカウントIDに関連するキャンペーン情報をページ単位で取得し、
+// ページごとに handler を呼び出します
+// updatedSince を指定した場合は updated_since パラメータで、その日時以降に更新されたキャンペーンのみを取得します
+// 外部API1の仕様では updated_since の境界（その日時ちょうどに更新されたキャンペーンを含むかどうか）が定められていないため、
+// モックとフィクスチャは境界を含めて返しますが、実際のAPIは含めない可能性があります
+func (r *CampaignRepositoryImpl) StreamCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time, handler func(campaigns []entity.Campaign) error) (int, error) {
+	handler = withCampaignSource(handler)
+
 	if r.mock {
-		return r.fetchMockCampaigns(ctx, accountID)
+		campaigns, err := r.fetchMockCampaigns(ctx, accountID)
+		if err != nil {
+			return 0, err
+		}
+		return 1, handler(filterCampaignsUpdatedSince(campaigns, updatedSince))
 	}
 
-	// 実際のAPIリクエストを行う場合の実装（今回は使用しない）
-	url := fmt.Sprintf("%s%s?account_id=%d", r.baseURL, "/api/campaigns", accountID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		log.Error().Err(err).Str("url", url).Uint("account_id", accountID).Msg("APIリクエストの作成に失敗しました")
-		return nil, err
+	// 実際のAPIリクエストを行う場合の実装
+	query := url.Values{}
+	query.Set("account_id", fmt.Sprint(accountID))
+	if updatedSince != nil {
+		query.Set("updated_since", updatedSince.UTC().Format(time.RFC3339Nano))
 	}
+	reqURL := fmt.Sprintf("%s%s?%s", r.baseURL, "/api/campaigns", query.Encode())
 
-	resp, err := r.client.Do(req)
+	pages, err := fetchPages(ctx, r.paginator, reqURL, r.apiClient.getJSON, handler)
 	if err != nil {
-		log.Error().Err(err).Str("url", url).Uint("account_id", accountID).Msg("APIリクエストの実行に失敗しました")
-		return nil, err
+		log.Error().Err(err).Str("url", reqURL).Uint("account_id", accountID).Int("pages", pages).Msg("キャンペーン情報の取得に失敗しました")
+		return pages, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf("APIがエラーを返しました: %s", resp.Status)
-		log.Error().Err(err).Str("url", url).Int("status_code", resp.StatusCode).Uint("account_id", accountID).Msg("APIエラー")
-		return nil, err
+	log.Debug().Uint("account_id", accountID).Int("pages", pages).Msg("キャンペーン情報の全ページを読み込みました")
+	return pages, nil
+}
+
+// filterCampaignsUpdatedSince は updatedSince 以降に更新されたキャンペーンのみを返します（nil の場合は全てのキャンペーンを返します）
+// updatedSince ちょうどに更新されたキャンペーンも返します。更新日時が設定されていないキャンペーンは更新されたものとして扱います
+func filterCampaignsUpdatedSince(campaigns []entity.Campaign, updatedSince *time.Time) []entity.Campaign {
+	if updatedSince == nil {
+		return campaigns
 	}
 
-	var campaigns []entity.Campaign
-	if err := json.NewDecoder(resp.Body).Decode(&campaigns); err != nil {
-		log.Error().Err(err).Str("url", url).Uint("account_id", accountID).Msg("APIレスポンスのデコードに失敗しました")
-		return nil, err
+	filtered := make([]entity.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.UpdatedAt.IsZero() || !campaign.UpdatedAt.Before(*updatedSince) {
+			filtered = append(filtered, campaign)
+		}
 	}
+	return filtered
+}
 
-	return campaigns, nil
+// withCampaignSource は取得したキャンペーンに取得元（api1）を設定してから handler を呼び出します
+func withCampaignSource(handler func(campaigns []entity.Campaign) error) func(campaigns []entity.Campaign) error {
+	return func(campaigns []entity.Campaign) error {
+		for i := range campaigns {
+			campaigns[i].Source = entity.SourceAPI1
+		}
+		return handler(campaigns)
+	}
 }
 
 // fetchMockCampaigns はモックのキャンペーンデータを返します
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -15,17 +15,35 @@ import (
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/secrets"
 )
 
+// DefaultTokenCacheTTL はAPIトークンをキャッシュするデフォルトの期間です
+const DefaultTokenCacheTTL = 5 * time.Minute
+
+// 認証ヘッダー名
+var authHeaderNames = []string{"Authorization", "X-API-Token", "X-API-Key"}
+
 // APIClient は外部API1のクライアントです
+// 認証ヘッダーはキャッシュされ、複数のゴルーチンから安全に利用できます
 type APIClient struct {
 	client         *http.Client
 	config         *config.Config
 	secretsManager secrets.Manager
+
+	mu         sync.Mutex
+	tokenCache *TokenCache
+	tokenTTL   time.Duration
+	now        func() time.Time
 }
 
 // TokenCache はトークンをキャッシュするための構造体です
 type TokenCache struct {
-	Token     string
-	ExpiresAt time.Time
+	HeaderName string
+	Token      string
+	ExpiresAt  time.Time
+}
+
+// IsValid はキャッシュされたトークンが有効期限内かどうかを返します
+func (t *TokenCache) IsValid(now time.Time) bool {
+	return t != nil && now.Before(t.ExpiresAt)
 }
 
 // NewAPIClient は新しいAPIClientを作成します
@@ -36,15 +54,60 @@ func NewAPIClient(cfg *config.Config, httpClient *http.Client, secretsManager se
 		}
 	}
 
+	tokenTTL := time.Duration(cfg.ExternalAPI1.TokenCacheTTL) * time.Second
+	if tokenTTL <= 0 {
+		tokenTTL = DefaultTokenCacheTTL
+	}
+
 	return &APIClient{
 		client:         httpClient,
 		config:         cfg,
 		secretsManager: secretsManager,
+		tokenTTL:       tokenTTL,
+		now:            time.Now,
 	}
 }
 
 // GetAuthorizationHeader は認証ヘッダーを取得します
+// 有効期限内のトークンがキャッシュされている場合はSecret Managerを呼び出しません
 func (c *APIClient) GetAuthorizationHeader(ctx context.Context) (string, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokenCache.IsValid(c.now()) {
+		return c.tokenCache.HeaderName, c.tokenCache.Token, nil
+	}
+
+	// 同時に複数のゴルーチンから呼ばれても取得は1回だけ行うよう、ロックを保持したまま取得する
+	headerName, headerValue, err := c.fetchAuthorizationHeader(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	c.tokenCache = &TokenCache{
+		HeaderName: headerName,
+		Token:      headerValue,
+		ExpiresAt:  c.now().Add(c.tokenTTL),
+	}
+
+	return headerName, headerValue, nil
+}
+
+// InvalidateToken はキャッシュされたトークンが token と一致する場合に破棄します
+// 401レスポンスを受け取った場合に呼び出し、次のリクエストでトークンを再取得させます
+// 他のゴルーチンが既に再取得したトークンは破棄しません
+func (c *APIClient) InvalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokenCache != nil && c.tokenCache.Token == token {
+		log.Info().Msg("キャッシュされたAPIトークンを破棄します")
+		c.tokenCache = nil
+	}
+}
+
+// fetchAuthorizationHeader はSecret Managerから認証ヘッダーを取得します
+func (c *APIClient) fetchAuthorizationHeader(ctx context.Context) (string, string, error) {
 	// Secret Managerが有効で、トークンのSecretIDが設定されている場合
 	if c.config.AWS.Secrets.Enabled && c.config.ExternalAPI1.TokenSecretID != "" {
 		log.Info().Msg("Secret ManagerからAPIトークンを取得します")
@@ -76,90 +139,50 @@ func (c *APIClient) GetAuthorizationHeader(ctx context.Context) (string, string,
 	return "X-API-Token", "dev-token-12345", nil
 }
 
-// CreateAuthenticatedRequest は認証情報を含むHTTPリクエストを作成します
-func (c *APIClient) CreateAuthenticatedRequest(ctx context.Context, method, url string, _ interface{}) (*http.Request, error) {
-	// リクエストを作成
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("リクエストの作成に失敗しました: %w", err)
-	}
-
-	// 認証ヘッダーを取得して設定
+// setAuthorizationHeader はリクエストに認証ヘッダーを設定します
+func (c *APIClient) setAuthorizationHeader(ctx context.Context, req *http.Request) error {
 	headerName, headerValue, err := c.GetAuthorizationHeader(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	for _, name := range authHeaderNames {
+		req.Header.Del(name)
+	}
 	if headerName != "" && headerValue != "" {
 		req.Header.Set(headerName, headerValue)
 	}
 
-	// Content-Typeを設定
-	req.Header.Set("Content-Type", "application/json")
-
-	return req, nil
+	return nil
 }
 
-// Request は外部API1へのリクエストを実行します
-func (c *APIClient) Request(ctx context.Context, method, path string, _ io.Reader) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", c.config.ExternalAPI1.BaseURL, path)
-
-	req, err := c.CreateAuthenticatedRequest(ctx, method, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// リクエストを実行
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("リクエストの実行に失敗しました: %w", err)
+// CreateAuthenticatedRequest は認証情報を含むHTTPリクエストを作成します
+// body が nil でない場合はJSONエンコードしてリクエストボディに設定します
+func (c *APIClient) CreateAuthenticatedRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error) {
+	b := c.NewRequest(method, url)
+	if body != nil {
+		b.JSON(body)
 	}
+	return b.Build(ctx)
+}
 
-	// エラーレスポンスの場合
-	if resp.StatusCode >= 400 {
-		defer resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("APIエラー: %s - %s", resp.Status, string(body))
-	}
+// Request は外部API1へのリクエストを実行します
+// エラーレスポンスの場合は *APIError を返します
+func (c *APIClient) Request(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.NewRequest(method, path).Body(body).Do(ctx)
+}
 
-	return resp, nil
+// getJSON は認証情報を付与して指定されたURLにGETリクエストを送信し、レスポンスを v にデコードします
+func (c *APIClient) getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	return c.NewRequest(http.MethodGet, rawURL).Decode(ctx, v)
 }
 
 // GetData は外部API1からデータを取得するサンプルメソッドです
 func (c *APIClient) GetData(ctx context.Context, dataID string) (map[string]interface{}, error) {
-	path := fmt.Sprintf("/data/%s", dataID)
-
-	resp, err := c.Request(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("レスポンスのデコードに失敗しました: %w", err)
-	}
-
-	return result, nil
+	return DoJSON[map[string]interface{}](ctx, c.NewRequest(http.MethodGet, fmt.Sprintf("/data/%s", dataID)))
 }
 
 // PostData は外部API1にデータを送信するサンプルメソッドです
 func (c *APIClient) PostData(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("データのエンコードに失敗しました: %w", err)
-	}
-
-	resp, err := c.Request(ctx, http.MethodPost, "/data", strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("レスポンスのデコードに失敗しました: %w", err)
-	}
-
-	return result, nil
+	return DoJSON[map[string]interface{}](ctx, c.NewRequest(http.MethodPost, "/data").JSON(data))
 }
@@ -0,0 +1,84 @@
+package externalapi1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// countingSecretsManager は呼び出し回数を記録し、呼び出しごとに異なるトークンを返すSecretsManagerです
+type countingSecretsManager struct {
+	calls int32
+}
+
+func (m *countingSecretsManager) GetSecret(_ context.Context, _ string) (string, error) {
+	n := atomic.AddInt32(&m.calls, 1)
+	return fmt.Sprintf(`{"bearer_token": "token-%d"}`, n), nil
+}
+
+func newTokenTestClient(baseURL string, sm *countingSecretsManager) *APIClient {
+	cfg := &config.Config{}
+	cfg.AWS.Secrets.Enabled = true
+	cfg.ExternalAPI1.BaseURL = baseURL
+	cfg.ExternalAPI1.TokenSecretID = "test/api/token"
+	return NewAPIClient(cfg, nil, sm)
+}
+
+func TestGetAuthorizationHeaderCachesToken(t *testing.T) {
+	sm := &countingSecretsManager{}
+	client := newTokenTestClient("", sm)
+
+	// 並列に呼び出してもSecret Managerの呼び出しは1回だけ
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, value, err := client.GetAuthorizationHeader(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "Bearer token-1", value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sm.calls))
+
+	// 有効期限が切れた場合は再取得する
+	client.now = func() time.Time { return time.Now().Add(DefaultTokenCacheTTL + time.Second) }
+	_, value, err := client.GetAuthorizationHeader(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", value)
+}
+
+func TestRequestRefreshesTokenOn401(t *testing.T) {
+	// token-1 は失効しているものとして401を返すサーバー
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	sm := &countingSecretsManager{}
+	client := newTokenTestClient(server.URL, sm)
+
+	data, err := client.GetData(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", data["id"])
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sm.calls))
+
+	// 再取得したトークンはキャッシュされる
+	_, err = client.GetData(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sm.calls))
+}
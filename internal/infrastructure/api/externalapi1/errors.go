@@ -0,0 +1,78 @@
+package externalapi1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize はエラーレスポンスから読み込むボディの上限サイズです
+const maxErrorBodySize = 64 * 1024
+
+// APIError は外部API1がエラーレスポンスを返した場合のエラーです
+// 呼び出し元は errors.As で取り出してステータスコードやエラーコードを確認できます
+type APIError struct {
+	StatusCode int    // HTTPステータスコード
+	Code       string // APIが返したエラーコード
+	Message    string // APIが返したエラーメッセージ
+	RequestID  string // リクエストID（問い合わせ用）
+	Body       string // レスポンスボディ（エラー形式を解析できなかった場合の確認用）
+}
+
+// Error はエラーメッセージを返します
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "APIエラー: ステータスコード %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", コード %s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ", メッセージ %s", e.Message)
+	} else if e.Body != "" {
+		fmt.Fprintf(&b, ", レスポンス %s", e.Body)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", リクエストID %s", e.RequestID)
+	}
+	return b.String()
+}
+
+// errorResponse はAPIのエラーレスポンス形式です
+// {"code": "...", "message": "..."} と {"error": {"code": "...", "message": "..."}} の両方に対応します
+type errorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+	Error     *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newAPIError はエラーレスポンスから APIError を作成します
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	var parsed errorResponse
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		apiErr.Code = parsed.Code
+		apiErr.Message = parsed.Message
+		if parsed.Error != nil {
+			apiErr.Code = parsed.Error.Code
+			apiErr.Message = parsed.Error.Message
+		}
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = parsed.RequestID
+		}
+	}
+
+	return apiErr
+}
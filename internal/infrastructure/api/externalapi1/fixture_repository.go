@@ -0,0 +1,124 @@
+package externalapi1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+// fixtureファイル名
+const (
+	accountsFixtureFile  = "accounts.json"
+	campaignsFixtureFile = "campaigns.json"
+)
+
+// FixtureAccountRepository はJSONファイルからアカウント情報を読み込むExternalAPI1AccountRepositoryの実装です
+type FixtureAccountRepository struct {
+	dir string
+}
+
+// NewFixtureAccountRepository は新しいFixtureAccountRepositoryインスタンスを作成します
+func NewFixtureAccountRepository(dir string) *FixtureAccountRepository {
+	return &FixtureAccountRepository{dir: dir}
+}
+
+// FetchAccounts はfixtureファイルから全てのアカウント情報を読み込みます
+func (r *FixtureAccountRepository) FetchAccounts(_ context.Context) ([]entity.Account, error) {
+	var accounts []entity.Account
+	if err := readFixture(filepath.Join(r.dir, accountsFixtureFile), &accounts); err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		accounts[i].Source = entity.SourceAPI1
+	}
+
+	log.Debug().Str("dir", r.dir).Int("count", len(accounts)).Msg("fixtureからアカウントデータを読み込みました")
+	return accounts, nil
+}
+
+// StreamAccounts はfixtureファイルから全てのアカウント情報を読み込み、1ページとして handler に渡します
+func (r *FixtureAccountRepository) StreamAccounts(ctx context.Context, handler func(accounts []entity.Account) error) (int, error) {
+	accounts, err := r.FetchAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return 1, handler(accounts)
+}
+
+// FetchAccountByID はfixtureファイルから指定されたIDのアカウント情報を読み込みます
+func (r *FixtureAccountRepository) FetchAccountByID(ctx context.Context, id int) (entity.Account, error) {
+	accounts, err := r.FetchAccounts(ctx)
+	if err != nil {
+		return entity.Account{}, err
+	}
+
+	for _, account := range accounts {
+		if id >= 0 && account.ID == uint(id) {
+			return account, nil
+		}
+	}
+
+	return entity.Account{}, fmt.Errorf("アカウントが見つかりません: ID %d", id)
+}
+
+// FixtureCampaignRepository はJSONファイルからキャンペーン情報を読み込むExternalAPI1CampaignRepositoryの実装です
+type FixtureCampaignRepository struct {
+	dir string
+}
+
+// NewFixtureCampaignRepository は新しいFixtureCampaignRepositoryインスタンスを作成します
+func NewFixtureCampaignRepository(dir string) *FixtureCampaignRepository {
+	return &FixtureCampaignRepository{dir: dir}
+}
+
+// FetchCampaignsByAccountID はfixtureファイルから指定されたアカウントIDに関連するキャンペーン情報を読み込みます
+// updatedSince を指定した場合は、その日時以降に更新されたキャンペーンのみを返します
+func (r *FixtureCampaignRepository) FetchCampaignsByAccountID(_ context.Context, accountID uint, updatedSince *time.Time) ([]entity.Campaign, error) {
+	var campaigns []entity.Campaign
+	if err := readFixture(filepath.Join(r.dir, campaignsFixtureFile), &campaigns); err != nil {
+		return nil, err
+	}
+
+	filtered := make([]entity.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.AccountID == accountID {
+			campaign.Source = entity.SourceAPI1
+			filtered = append(filtered, campaign)
+		}
+	}
+
+	filtered = filterCampaignsUpdatedSince(filtered, updatedSince)
+
+	log.Debug().Str("dir", r.dir).Uint("account_id", accountID).Int("count", len(filtered)).Msg("fixtureからキャンペーンデータを読み込みました")
+	return filtered, nil
+}
+
+// StreamCampaignsByAccountID はfixtureファイルからキャンペーン情報を読み込み、1ページとして handler に渡します
+func (r *FixtureCampaignRepository) StreamCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time, handler func(campaigns []entity.Campaign) error) (int, error) {
+	campaigns, err := r.FetchCampaignsByAccountID(ctx, accountID, updatedSince)
+	if err != nil {
+		return 0, err
+	}
+	return 1, handler(campaigns)
+}
+
+// readFixture はJSONファイルを読み込み、v にデコードします
+func readFixture(path string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("fixtureファイルの読み込みに失敗しました: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("fixtureファイルのパースに失敗しました（%s）: %w", path, err)
+	}
+
+	return nil
+}
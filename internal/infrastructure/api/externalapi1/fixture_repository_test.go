@@ -0,0 +1,80 @@
+package externalapi1
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+func TestNewRepositoriesBySource(t *testing.T) {
+	cfg := &config.Config{}
+
+	// 未設定の場合はモックを使用
+	accountRepo, err := NewAccountRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+	assert.True(t, accountRepo.(*AccountRepositoryImpl).mock)
+
+	// live の場合は実APIを使用
+	cfg.ExternalAPI1.Source = SourceLive
+	campaignRepo, err := NewCampaignRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+	assert.False(t, campaignRepo.(*CampaignRepositoryImpl).mock)
+
+	// fixture の場合はfixtureリポジトリを使用
+	cfg.ExternalAPI1.Source = SourceFixture
+	accountRepo, err = NewAccountRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+	assert.IsType(t, &FixtureAccountRepository{}, accountRepo)
+
+	// 未対応の値はエラー
+	cfg.ExternalAPI1.Source = "unknown"
+	_, err = NewAccountRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.Error(t, err)
+}
+
+func TestFixtureRepositories(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, accountsFixtureFile), []byte(`[{"id":1,"name":"A"},{"id":2,"name":"B"}]`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, campaignsFixtureFile), []byte(`[{"id":101,"account_id":1},{"id":201,"account_id":2},{"id":102,"account_id":1}]`), 0o600))
+
+	ctx := context.Background()
+
+	accountRepo := NewFixtureAccountRepository(dir)
+	accounts, err := accountRepo.FetchAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+
+	account, err := accountRepo.FetchAccountByID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "B", account.Name)
+
+	_, err = accountRepo.FetchAccountByID(ctx, 3)
+	assert.Error(t, err)
+
+	// アカウントIDで絞り込まれること
+	campaignRepo := NewFixtureCampaignRepository(dir)
+	campaigns, err := campaignRepo.FetchCampaignsByAccountID(ctx, 1, nil)
+	assert.NoError(t, err)
+	assert.Len(t, campaigns, 2)
+
+	// 基準日時以降（基準日時ちょうどを含む）に更新されたキャンペーンのみを返すこと
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, campaignsFixtureFile), []byte(`[
+		{"id":101,"account_id":1,"updated_at":"2024-04-30T23:59:59Z"},
+		{"id":102,"account_id":1,"updated_at":"2024-05-01T00:00:00Z"}
+	]`), 0o600))
+	campaigns, err = campaignRepo.FetchCampaignsByAccountID(ctx, 1, &since)
+	assert.NoError(t, err)
+	assert.Len(t, campaigns, 1)
+	assert.Equal(t, uint(102), campaigns[0].ID)
+
+	// ファイルが存在しない場合はエラー
+	_, err = NewFixtureCampaignRepository(t.TempDir()).FetchCampaignsByAccountID(ctx, 1, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,195 @@
+package externalapi1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// ページネーション方式
+const (
+	PaginationNone   = "none"   // ページネーションなし（単一のJSON配列を返す）
+	PaginationCursor = "cursor" // next_cursor または next のリンクで次ページを取得する
+	PaginationPage   = "page"   // page / per_page パラメータで次ページを取得する
+	PaginationOffset = "offset" // offset / limit パラメータで次ページを取得する
+)
+
+// DefaultPageSize は1ページあたりのデフォルト取得件数です
+const DefaultPageSize = 100
+
+// pageEnvelope はページネーションされたAPIレスポンスの形式です
+type pageEnvelope[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor"`
+	Next       string `json:"next"`     // 次ページのURL（指定された場合は方式に関わらず優先して使用）
+	HasMore    *bool  `json:"has_more"` // 次ページの有無（指定されない場合は空のページを最終ページとみなす）
+}
+
+// pageGetter は指定されたURLにGETリクエストを送信し、レスポンスを v にデコードします
+type pageGetter func(ctx context.Context, rawURL string, v interface{}) error
+
+// paginator は一覧取得APIのページネーションを処理します
+type paginator struct {
+	style    string
+	pageSize int
+	maxPages int
+}
+
+// newPaginator は設定から paginator を作成します
+func newPaginator(cfg config.PaginationConfig) (*paginator, error) {
+	style := cfg.Style
+	switch style {
+	case "":
+		style = PaginationNone
+	case PaginationNone, PaginationCursor, PaginationPage, PaginationOffset:
+	default:
+		return nil, fmt.Errorf("未対応のページネーション方式です: %s（none, cursor, page, offset のいずれかを指定してください）", cfg.Style)
+	}
+
+	if cfg.PageSize < 0 || cfg.MaxPages < 0 {
+		return nil, fmt.Errorf("ページネーションの設定値が不正です: page_size=%d, max_pages=%d", cfg.PageSize, cfg.MaxPages)
+	}
+
+	pageSize := cfg.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &paginator{
+		style:    style,
+		pageSize: pageSize,
+		maxPages: cfg.MaxPages,
+	}, nil
+}
+
+// fetchPages は一覧取得APIを最後のページまで順に呼び出し、ページごとに handler を呼び出します
+// 読み込んだページ数を返します
+func fetchPages[T any](ctx context.Context, p *paginator, baseURL string, get pageGetter, handler func([]T) error) (int, error) {
+	// ページネーションなしの場合は単一のJSON配列として扱う
+	if p.style == PaginationNone {
+		var items []T
+		if err := get(ctx, baseURL, &items); err != nil {
+			return 0, err
+		}
+		return 1, handler(items)
+	}
+
+	pages := 0
+	offset := 0
+	cursor := ""
+	nextURL := ""
+	for {
+		// コンテキストがキャンセルされた場合は中断
+		if err := ctx.Err(); err != nil {
+			return pages, fmt.Errorf("ページの取得を中断しました（%dページ読み込み済み）: %w", pages, err)
+		}
+
+		if p.maxPages > 0 && pages >= p.maxPages {
+			return pages, fmt.Errorf("読み込むページ数が上限（%d）に達しました", p.maxPages)
+		}
+
+		reqURL := nextURL
+		if reqURL == "" {
+			var err error
+			reqURL, err = p.pageURL(baseURL, pages, offset, cursor)
+			if err != nil {
+				return pages, err
+			}
+		}
+
+		var page pageEnvelope[T]
+		if err := get(ctx, reqURL, &page); err != nil {
+			return pages, err
+		}
+		pages++
+		offset += len(page.Data)
+
+		log.Debug().Str("url", reqURL).Int("page", pages).Int("count", len(page.Data)).Msg("ページを読み込みました")
+
+		if err := handler(page.Data); err != nil {
+			return pages, err
+		}
+
+		// 次ページの有無を判定
+		if page.HasMore != nil && !*page.HasMore {
+			return pages, nil
+		}
+
+		if page.Next != "" {
+			next, err := resolveNextURL(baseURL, page.Next)
+			if err != nil {
+				return pages, err
+			}
+			if next == reqURL {
+				return pages, fmt.Errorf("次ページのURLが現在のページと同じです: %s", next)
+			}
+			nextURL = next
+			continue
+		}
+		nextURL = ""
+
+		switch p.style {
+		case PaginationCursor:
+			if page.NextCursor == "" {
+				return pages, nil
+			}
+			if page.NextCursor == cursor {
+				return pages, fmt.Errorf("次ページのカーソルが現在のページと同じです: %s", cursor)
+			}
+			cursor = page.NextCursor
+		default:
+			// has_more が返されない場合は、空のページを最終ページとみなす
+			// 上流がページサイズの上限を設定値より小さく制限している場合に途中のページで終了しないよう、取得件数では判定しない
+			if page.HasMore == nil && len(page.Data) == 0 {
+				return pages, nil
+			}
+		}
+	}
+}
+
+// pageURL は読み込み済みのページ数・件数とカーソルから次ページのURLを組み立てます
+// 上流がページサイズを制限している場合に読み飛ばさないよう、offset は読み込み済みの件数から求めます
+func (p *paginator) pageURL(baseURL string, pagesRead, itemsRead int, cursor string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("URLの解析に失敗しました: %w", err)
+	}
+
+	q := u.Query()
+	switch p.style {
+	case PaginationCursor:
+		q.Set("limit", strconv.Itoa(p.pageSize))
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+	case PaginationPage:
+		q.Set("page", strconv.Itoa(pagesRead+1))
+		q.Set("per_page", strconv.Itoa(p.pageSize))
+	case PaginationOffset:
+		q.Set("offset", strconv.Itoa(itemsRead))
+		q.Set("limit", strconv.Itoa(p.pageSize))
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// resolveNextURL は next リンクを絶対URLに変換します（相対パスの場合は baseURL を基準にします）
+func resolveNextURL(baseURL, next string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("URLの解析に失敗しました: %w", err)
+	}
+
+	ref, err := url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("次ページのURLの解析に失敗しました: %w", err)
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,170 @@
+package externalapi1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+func newLiveConfig(baseURL string, pagination config.PaginationConfig) *config.Config {
+	cfg := &config.Config{}
+	cfg.ExternalAPI1.BaseURL = baseURL
+	cfg.ExternalAPI1.Source = SourceLive
+	cfg.ExternalAPI1.Pagination = pagination
+	return cfg
+}
+
+func TestStreamAccountsCursor(t *testing.T) {
+	// 3ページ目で next_cursor が空になるサーバー
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+
+		var body map[string]interface{}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			body = map[string]interface{}{"data": []entity.Account{{ID: 1}, {ID: 2}}, "next_cursor": "c2"}
+		case "c2":
+			body = map[string]interface{}{"data": []entity.Account{{ID: 3}, {ID: 4}}, "next_cursor": "c3"}
+		case "c3":
+			body = map[string]interface{}{"data": []entity.Account{{ID: 5}}, "next_cursor": ""}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	cfg := newLiveConfig(server.URL, config.PaginationConfig{Style: PaginationCursor, PageSize: 2})
+	repo, err := NewAccountRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+
+	var ids []uint
+	pages, err := repo.StreamAccounts(context.Background(), func(accounts []entity.Account) error {
+		for _, account := range accounts {
+			ids = append(ids, account.ID)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []uint{1, 2, 3, 4, 5}, ids)
+}
+
+func TestStreamCampaignsPage(t *testing.T) {
+	// ページごとに件数が異なり（2件、1件、1件）、4ページ目が空になるサーバー
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "7", r.URL.Query().Get("account_id"))
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		data := []entity.Campaign{}
+		switch page {
+		case 1:
+			data = []entity.Campaign{{ID: 1, AccountID: 7}, {ID: 2, AccountID: 7}}
+		case 2:
+			data = []entity.Campaign{{ID: 3, AccountID: 7}}
+		case 3:
+			data = []entity.Campaign{{ID: 4, AccountID: 7}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	cfg := newLiveConfig(server.URL, config.PaginationConfig{Style: PaginationPage, PageSize: 2})
+	repo, err := NewCampaignRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+
+	// 取得件数がページサイズに満たないページがあっても、空のページまで読み込む
+	campaigns, err := repo.FetchCampaignsByAccountID(context.Background(), 7, nil)
+	assert.NoError(t, err)
+	assert.Len(t, campaigns, 4)
+}
+
+func TestStreamAccountsOffsetCappedPageSize(t *testing.T) {
+	// 上流がページサイズを2件に制限し、has_more を返さないサーバー
+	all := []entity.Account{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "100", r.URL.Query().Get("limit"))
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		start := min(offset, len(all))
+		end := min(start+2, len(all))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": all[start:end]})
+	}))
+	defer server.Close()
+
+	cfg := newLiveConfig(server.URL, config.PaginationConfig{Style: PaginationOffset, PageSize: 100})
+	repo, err := NewAccountRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+
+	// 1ページ目で終了せず、取得した件数だけ offset を進めて空のページまで読み込む
+	var ids []uint
+	pages, err := repo.StreamAccounts(context.Background(), func(accounts []entity.Account) error {
+		for _, account := range accounts {
+			ids = append(ids, account.ID)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pages)
+	assert.Equal(t, []uint{1, 2, 3, 4, 5}, ids)
+}
+
+func TestStreamCampaignsUpdatedSince(t *testing.T) {
+	since := time.Date(2024, 5, 1, 3, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	// updated_since がUTCのRFC3339形式で渡されること
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "7", r.URL.Query().Get("account_id"))
+		assert.Equal(t, "2024-04-30T18:00:00Z", r.URL.Query().Get("updated_since"))
+		_ = json.NewEncoder(w).Encode([]entity.Campaign{{ID: 1, AccountID: 7}})
+	}))
+	defer server.Close()
+
+	cfg := newLiveConfig(server.URL, config.PaginationConfig{Style: PaginationNone})
+	repo, err := NewCampaignRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+
+	campaigns, err := repo.FetchCampaignsByAccountID(context.Background(), 7, &since)
+	assert.NoError(t, err)
+	assert.Len(t, campaigns, 1)
+}
+
+func TestStreamAccountsCancelled(t *testing.T) {
+	// 常に次ページがあるサーバー
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":     []entity.Account{{ID: uint(offset + 1)}},
+			"has_more": true,
+		})
+	}))
+	defer server.Close()
+
+	cfg := newLiveConfig(server.URL, config.PaginationConfig{Style: PaginationOffset, PageSize: 1})
+	repo, err := NewAccountRepository(cfg, NewAPIClient(cfg, nil, nil))
+	assert.NoError(t, err)
+
+	// 2ページ読み込んだ時点でキャンセル
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pages, err := repo.StreamAccounts(ctx, func(accounts []entity.Account) error {
+		if accounts[0].ID == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, pages)
+}
+
+func TestNewPaginatorInvalidStyle(t *testing.T) {
+	_, err := newPaginator(config.PaginationConfig{Style: "link"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package externalapi1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RequestBuilder は外部API1へのリクエストを組み立てます
+type RequestBuilder struct {
+	client  *APIClient
+	method  string
+	path    string
+	query   url.Values
+	headers http.Header
+	body    io.Reader
+	jsonErr error
+}
+
+// NewRequest は指定されたメソッドとパスのリクエストビルダーを作成します
+// path が http:// または https:// で始まる場合は BaseURL を付与せずにそのまま使用します
+func (c *APIClient) NewRequest(method, path string) *RequestBuilder {
+	return &RequestBuilder{
+		client:  c,
+		method:  method,
+		path:    path,
+		query:   url.Values{},
+		headers: http.Header{},
+	}
+}
+
+// Query はクエリパラメータを追加します
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// Header はリクエストヘッダーを設定します
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.headers.Set(key, value)
+	return b
+}
+
+// JSON はリクエストボディとして v をJSONエンコードして送信します
+func (b *RequestBuilder) JSON(v interface{}) *RequestBuilder {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.jsonErr = fmt.Errorf("リクエストボディのエンコードに失敗しました: %w", err)
+		return b
+	}
+	b.body = bytes.NewReader(data)
+	return b
+}
+
+// Body はリクエストボディをそのまま送信します
+func (b *RequestBuilder) Body(body io.Reader) *RequestBuilder {
+	b.body = body
+	return b
+}
+
+// Build は認証情報を含むHTTPリクエストを作成します
+func (b *RequestBuilder) Build(ctx context.Context) (*http.Request, error) {
+	if b.jsonErr != nil {
+		return nil, b.jsonErr
+	}
+
+	rawURL := b.path
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		rawURL = b.client.config.ExternalAPI1.BaseURL + b.path
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("URLの解析に失敗しました: %w", err)
+	}
+	if len(b.query) > 0 {
+		q := u.Query()
+		for key, values := range b.query {
+			for _, value := range values {
+				q.Add(key, value)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, b.method, u.String(), b.body)
+	if err != nil {
+		return nil, fmt.Errorf("リクエストの作成に失敗しました: %w", err)
+	}
+
+	// 認証ヘッダーを取得して設定
+	if err := b.client.setAuthorizationHeader(ctx, req); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if b.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range b.headers {
+		req.Header[key] = values
+	}
+
+	return req, nil
+}
+
+// Do はリクエストを実行します
+// 401レスポンスの場合はトークンを再取得して1回だけ再送します
+// 2xx以外のレスポンスの場合は *APIError を返します
+func (b *RequestBuilder) Do(ctx context.Context) (*http.Response, error) {
+	req, err := b.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエストの実行に失敗しました: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && (req.Body == nil || req.GetBody != nil) {
+		resp, err = b.retryWithNewToken(ctx, req, resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+
+	return resp, nil
+}
+
+// retryWithNewToken はキャッシュされたトークンを破棄し、再取得したトークンでリクエストを再送します
+func (b *RequestBuilder) retryWithNewToken(ctx context.Context, req *http.Request, resp *http.Response) (*http.Response, error) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	// 失敗したリクエストで使用したトークンを破棄
+	for _, name := range authHeaderNames {
+		if token := req.Header.Get(name); token != "" {
+			b.client.InvalidateToken(token)
+		}
+	}
+
+	log.Warn().Str("method", req.Method).Str("url", req.URL.Redacted()).Msg("認証エラーのため、APIトークンを再取得して再送します")
+
+	retry := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("リクエストボディの再作成に失敗しました: %w", err)
+		}
+		retry.Body = body
+	}
+	if err := b.client.setAuthorizationHeader(ctx, retry); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.client.Do(retry)
+	if err != nil {
+		return nil, fmt.Errorf("リクエストの実行に失敗しました: %w", err)
+	}
+	return resp, nil
+}
+
+// Decode はリクエストを実行し、レスポンスボディを v にデコードします
+// レスポンスボディが空の場合はデコードを行いません
+func (b *RequestBuilder) Decode(ctx context.Context, v interface{}) error {
+	resp, err := b.Do(ctx)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("レスポンスのデコードに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// DoJSON はリクエストを実行し、レスポンスボディを T 型にデコードして返します
+func DoJSON[T any](ctx context.Context, b *RequestBuilder) (T, error) {
+	var result T
+	if err := b.Decode(ctx, &result); err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
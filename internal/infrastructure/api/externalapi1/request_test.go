@@ -0,0 +1,88 @@
+package externalapi1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/data", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("dry_run"))
+		assert.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "dev-token-12345", r.Header.Get("X-API-Token"))
+
+		// リクエストボディが送信されていること
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "test", body["name"])
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "1", "name": body["name"]})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI1.BaseURL = server.URL
+	client := NewAPIClient(cfg, nil, nil)
+
+	type response struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	resp, err := DoJSON[response](context.Background(), client.NewRequest(http.MethodPost, "/data").
+		Query("dry_run", "1").
+		Header("X-Trace-Id", "trace-1").
+		JSON(map[string]interface{}{"name": "test"}))
+	assert.NoError(t, err)
+	assert.Equal(t, response{ID: "1", Name: "test"}, resp)
+}
+
+func TestPostDataSendsBody(t *testing.T) {
+	// 受け取ったボディをそのまま返すサーバー
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI1.BaseURL = server.URL
+	client := NewAPIClient(cfg, nil, nil)
+
+	result, err := client.PostData(context.Background(), map[string]interface{}{"name": "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", result["name"])
+}
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": "INVALID_ARGUMENT", "message": "name is required"}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI1.BaseURL = server.URL
+	client := NewAPIClient(cfg, nil, nil)
+
+	_, err := client.GetData(context.Background(), "1")
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "INVALID_ARGUMENT", apiErr.Code)
+	assert.Equal(t, "name is required", apiErr.Message)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+}
@@ -0,0 +1,25 @@
+package externalapi1
+
+import (
+	"fmt"
+)
+
+// データ取得元
+const (
+	SourceMock    = "mock"    // モックデータを返す
+	SourceLive    = "live"    // 実際のAPIにHTTPリクエストを送信する
+	SourceFixture = "fixture" // fixture_dir に配置したJSONファイルを読み込む
+)
+
+// resolveSource は設定値からデータ取得元を決定します
+// 未設定の場合は従来通りモックを使用します
+func resolveSource(source string) (string, error) {
+	switch source {
+	case "":
+		return SourceMock, nil
+	case SourceMock, SourceLive, SourceFixture:
+		return source, nil
+	default:
+		return "", fmt.Errorf("未対応のExternalAPI1データ取得元です: %s（mock, live, fixture のいずれかを指定してください）", source)
+	}
+}
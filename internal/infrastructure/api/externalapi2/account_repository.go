@@ -0,0 +1,132 @@
+package externalapi2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// mockManagerCustomerID はモック時に使用するマネージャーアカウントの顧客IDです
+const mockManagerCustomerID = "1000000000"
+
+// AccountRepositoryImpl はExternalAPI2AccountRepositoryインターフェースの実装です
+// ログイン用のマネージャーアカウント（login_customer_id）配下の顧客アカウントを取得します
+type AccountRepositoryImpl struct {
+	client            searchStreamer
+	managerCustomerID string
+}
+
+// NewAccountRepository は設定されたデータ取得元（mock, live）に応じたExternalAPI2AccountRepositoryを作成します
+func NewAccountRepository(cfg *config.Config, client *Client) (repository.ExternalAPI2AccountRepository, error) {
+	switch cfg.ExternalAPI2.Source {
+	case "", SourceMock:
+		log.Debug().Str("source", SourceMock).Msg("ExternalAPI2アカウントリポジトリのデータ取得元を設定しました")
+		return &AccountRepositoryImpl{client: newMockHierarchyClient(), managerCustomerID: mockManagerCustomerID}, nil
+	case SourceLive:
+		log.Debug().Str("source", SourceLive).Msg("ExternalAPI2アカウントリポジトリのデータ取得元を設定しました")
+		return &AccountRepositoryImpl{client: client, managerCustomerID: cfg.ExternalAPI2.LoginCustomerID}, nil
+	default:
+		return nil, fmt.Errorf("未対応のExternalAPI2データ取得元です: %s（mock, live のいずれかを指定してください）", cfg.ExternalAPI2.Source)
+	}
+}
+
+// FetchAccounts はマネージャーアカウント配下の全ての顧客アカウントを取得します
+// サブマネージャーアカウント自体は含めません
+func (r *AccountRepositoryImpl) FetchAccounts(ctx context.Context) ([]entity.Account, error) {
+	clients, err := walkCustomerHierarchy(ctx, r.client, r.managerCustomerID)
+	if err != nil {
+		log.Error().Err(err).Str("manager_customer_id", r.managerCustomerID).Msg("アカウント階層の取得に失敗しました")
+		return nil, err
+	}
+
+	accounts := make([]entity.Account, 0, len(clients))
+	for _, client := range clients {
+		if client.Manager {
+			continue
+		}
+
+		account, err := toAccount(client)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	log.Info().
+		Str("manager_customer_id", r.managerCustomerID).
+		Int("customers", len(clients)).
+		Int("client_accounts", len(accounts)).
+		Msg("マネージャーアカウント配下のアカウントを取得しました")
+
+	return accounts, nil
+}
+
+// StreamAccounts はマネージャーアカウント配下の全ての顧客アカウントを1ページとして handler に渡します
+func (r *AccountRepositoryImpl) StreamAccounts(ctx context.Context, handler func(accounts []entity.Account) error) (int, error) {
+	accounts, err := r.FetchAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return 1, handler(accounts)
+}
+
+// toAccount は顧客アカウントをアカウントエンティティに変換します
+func toAccount(client CustomerClient) (entity.Account, error) {
+	id, err := strconv.ParseUint(client.CustomerID, 10, strconv.IntSize)
+	if err != nil {
+		return entity.Account{}, fmt.Errorf("顧客IDの変換に失敗しました: %s: %w", client.CustomerID, err)
+	}
+
+	return entity.Account{
+		ID:     uint(id),
+		Source: entity.SourceAPI2,
+		Name:   client.Name,
+		Status: toAccountStatus(client.Status),
+	}, nil
+}
+
+// toAccountStatus は外部API2の顧客ステータスを共通のステータスに変換します
+func toAccountStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "ENABLED":
+		return "active"
+	case "CANCELED", "SUSPENDED", "CLOSED":
+		return "inactive"
+	default:
+		return strings.ToLower(status)
+	}
+}
+
+// newMockHierarchyClient はマネージャー → サブマネージャー → 顧客アカウントの階層を持つモッククライアントを作成します
+func newMockHierarchyClient() *MockClient {
+	client := NewMockClient()
+	client.SetMockData("searchStream:"+mockManagerCustomerID, []map[string]interface{}{
+		mockCustomerClientRow("2000000001", "モック顧客アカウント1", "ENABLED", false),
+		mockCustomerClientRow("2000000002", "モック顧客アカウント2", "CANCELED", false),
+		mockCustomerClientRow("1100000000", "モックサブマネージャー", "ENABLED", true),
+	})
+	client.SetMockData("searchStream:1100000000", []map[string]interface{}{
+		mockCustomerClientRow("2000000003", "モック顧客アカウント3", "ENABLED", false),
+	})
+	return client
+}
+
+// mockCustomerClientRow は customer_client クエリの結果行のモックデータを作成します
+func mockCustomerClientRow(customerID, name, status string, manager bool) map[string]interface{} {
+	return map[string]interface{}{
+		"customerClient": map[string]interface{}{
+			"clientCustomer":  "customers/" + customerID,
+			"id":              customerID,
+			"descriptiveName": name,
+			"manager":         manager,
+			"status":          status,
+		},
+	}
+}
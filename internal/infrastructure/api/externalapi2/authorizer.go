@@ -0,0 +1,231 @@
+package externalapi2
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/secrets"
+)
+
+// callbackPath はループバックリダイレクトを受け取るパスです
+const callbackPath = "/oauth2/callback"
+
+// AuthorizerImpl はExternalAPI2Authorizerインターフェースの実装です
+type AuthorizerImpl struct {
+	client        *Client
+	config        *config.Config
+	secretsWriter secrets.Writer
+}
+
+// NewAuthorizer は新しいAuthorizerImplを作成します
+func NewAuthorizer(cfg *config.Config, client *Client, secretsWriter secrets.Writer) repository.ExternalAPI2Authorizer {
+	return &AuthorizerImpl{
+		client:        client,
+		config:        cfg,
+		secretsWriter: secretsWriter,
+	}
+}
+
+// authorizeResult はリダイレクトで受け取った認可コードまたはエラーです
+type authorizeResult struct {
+	code string
+	err  error
+}
+
+// Authorize は認可コードフローを実行し、リフレッシュトークンを含む認証情報を返します
+// ローカルポートでリダイレクトを待ち受け、PKCEで認可コードを交換します
+func (a *AuthorizerImpl) Authorize(ctx context.Context, opts repository.ExternalAPI2AuthorizeOptions) (*repository.ExternalAPI2Credentials, error) {
+	oauthConfig, err := a.oauthConfig(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", opts.Port))
+	if err != nil {
+		return nil, fmt.Errorf("リダイレクト用のポートの待ち受けに失敗しました: %w", err)
+	}
+	oauthConfig.RedirectURL = fmt.Sprintf("http://%s%s", listener.Addr().String(), callbackPath)
+
+	state, err := randomState()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	results := make(chan authorizeResult, 1)
+	server := &http.Server{
+		Handler:           callbackHandler(state, results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			select {
+			case results <- authorizeResult{err: fmt.Errorf("リダイレクト用サーバーの起動に失敗しました: %w", err)}:
+			default:
+			}
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	// refresh_token を必ず発行させるため、オフラインアクセスと同意画面の再表示を要求する
+	authURL := oauthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce, oauth2.S256ChallengeOption(verifier))
+	log.Info().Str("redirect_url", oauthConfig.RedirectURL).Msg("OAuth2認可のリダイレクトを待ち受けます")
+	if opts.OnAuthURL != nil {
+		opts.OnAuthURL(authURL)
+	}
+
+	var result authorizeResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("OAuth2認可を中断しました: %w", ctx.Err())
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := oauthConfig.Exchange(a.client.withHTTPClient(ctx), result.code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("認可コードの交換に失敗しました: %w", err)
+	}
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("リフレッシュトークンが発行されませんでした（アプリのアクセス権を取り消してから再度実行してください）")
+	}
+
+	log.Info().Msg("OAuth2認可が完了しました")
+	return &repository.ExternalAPI2Credentials{
+		ClientID:     oauthConfig.ClientID,
+		ClientSecret: oauthConfig.ClientSecret,
+		RefreshToken: token.RefreshToken,
+	}, nil
+}
+
+// SaveCredentials は認証情報をOAuth2認証情報のシークレット（oauth2_secret_id）に保存します
+// シークレットに含まれるその他の項目は保持します
+func (a *AuthorizerImpl) SaveCredentials(ctx context.Context, credentials *repository.ExternalAPI2Credentials) error {
+	secretID := a.config.ExternalAPI2.OAuth2SecretID
+	if !a.config.AWS.Secrets.Enabled || secretID == "" {
+		return fmt.Errorf("保存先のシークレットが設定されていません（aws.secrets.enabled と external_api2.oauth2_secret_id を設定してください）")
+	}
+
+	// 既存のシークレットがあれば読み込み、その他の項目を保持する
+	values := map[string]interface{}{}
+	if current, err := a.client.secretsManager.GetSecret(ctx, secretID); err == nil {
+		if err := json.Unmarshal([]byte(current), &values); err != nil {
+			return fmt.Errorf("既存のOAuth2認証情報の解析に失敗しました: %w", err)
+		}
+	} else {
+		log.Debug().Err(err).Str("secret_id", secretID).Msg("既存のOAuth2認証情報を取得できないため新しく作成します")
+	}
+
+	values["client_id"] = credentials.ClientID
+	values["client_secret"] = credentials.ClientSecret
+	values["refresh_token"] = credentials.RefreshToken
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("OAuth2認証情報のエンコードに失敗しました: %w", err)
+	}
+	if err := a.secretsWriter.PutSecret(ctx, secretID, string(data)); err != nil {
+		return err
+	}
+
+	log.Info().Str("secret_id", secretID).Msg("OAuth2認証情報を保存しました")
+	return nil
+}
+
+// oauthConfig は認可に使用するOAuth2設定を作成します
+// クライアントID・シークレットはオプション、Secret Manager、設定ファイルの順に優先します
+func (a *AuthorizerImpl) oauthConfig(ctx context.Context, opts repository.ExternalAPI2AuthorizeOptions) (*oauth2.Config, error) {
+	a.client.mu.Lock()
+	oauthConfig := *a.client.oauthConfig
+	a.client.mu.Unlock()
+
+	if a.config.AWS.Secrets.Enabled && a.config.ExternalAPI2.OAuth2SecretID != "" {
+		// 初回の認可ではシークレットがまだ存在しない場合があるため、取得できなくても続行する
+		oauthSecret, err := a.client.loadOAuth2Secret(ctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("Secret ManagerからOAuth2認証情報を取得できないため設定ファイルの値を使用します")
+		} else {
+			if oauthSecret.ClientID != "" {
+				oauthConfig.ClientID = oauthSecret.ClientID
+			}
+			if oauthSecret.ClientSecret != "" {
+				oauthConfig.ClientSecret = oauthSecret.ClientSecret
+			}
+		}
+	}
+
+	if opts.ClientID != "" {
+		oauthConfig.ClientID = opts.ClientID
+	}
+	if opts.ClientSecret != "" {
+		oauthConfig.ClientSecret = opts.ClientSecret
+	}
+
+	if oauthConfig.ClientID == "" || oauthConfig.ClientSecret == "" {
+		return nil, fmt.Errorf("OAuth2クライアントID・シークレットが設定されていません")
+	}
+	return &oauthConfig, nil
+}
+
+// callbackHandler はリダイレクトを受け取り、state を検証して認可コードを results に送信します
+func callbackHandler(state string, results chan<- authorizeResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var result authorizeResult
+		switch {
+		case query.Get("state") != state:
+			// 別のリクエストによるリダイレクトは無視して待ち続ける
+			http.Error(w, "state が一致しません", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("OAuth2認可が拒否されました: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			result.err = fmt.Errorf("リダイレクトに認可コードが含まれていません")
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, "認可に失敗しました。ターミナルを確認してください。", http.StatusBadRequest)
+		} else {
+			_, _ = fmt.Fprintln(w, "認可が完了しました。このウィンドウを閉じてターミナルに戻ってください。")
+		}
+
+		// 最初の結果のみを受け取る
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	return mux
+}
+
+// randomState はCSRF対策の state パラメーターを生成します
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("state の生成に失敗しました: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,76 @@
+package externalapi2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+func TestAuthorize(t *testing.T) {
+	var challenge string
+
+	// 認可コードと code_verifier を検証してトークンを返すトークンエンドポイント
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "auth-code", r.FormValue("code"))
+		assert.Equal(t, challenge, oauth2.S256ChallengeFromVerifier(r.FormValue("code_verifier")))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "access", "refresh_token": "refresh-1", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.ClientID = "client-id"
+	cfg.ExternalAPI2.ClientSecret = "client-secret"
+	client := NewClient(cfg, nil, nil, nil)
+	client.oauthConfig.Endpoint.TokenURL = server.URL
+
+	authorizer := NewAuthorizer(cfg, client, nil)
+	credentials, err := authorizer.Authorize(context.Background(), repository.ExternalAPI2AuthorizeOptions{
+		OnAuthURL: func(authURL string) {
+			// ブラウザの代わりにリダイレクト先へアクセスする
+			u, err := url.Parse(authURL)
+			assert.NoError(t, err)
+			query := u.Query()
+			assert.Equal(t, "offline", query.Get("access_type"))
+			assert.Equal(t, "S256", query.Get("code_challenge_method"))
+			challenge = query.Get("code_challenge")
+
+			redirect := query.Get("redirect_uri") + "?" + url.Values{"state": {query.Get("state")}, "code": {"auth-code"}}.Encode()
+			go func() {
+				resp, err := http.Get(redirect)
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &repository.ExternalAPI2Credentials{ClientID: "client-id", ClientSecret: "client-secret", RefreshToken: "refresh-1"}, credentials)
+}
+
+func TestCallbackHandler(t *testing.T) {
+	results := make(chan authorizeResult, 1)
+	handler := callbackHandler("state-1", results)
+
+	// state が一致しないリダイレクトは無視する
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, callbackPath+"?state=other&code=x", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, results)
+
+	// 認可が拒否された場合はエラー
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, callbackPath+"?state=state-1&error=access_denied", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	result := <-results
+	assert.Error(t, result.err)
+}
@@ -0,0 +1,69 @@
+package externalapi2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CampaignPayload は外部API2が返すキャンペーン情報です
+type CampaignPayload struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Status    string  `json:"status"` // ENABLED, PAUSED, REMOVED など
+	Budget    float64 `json:"budget"`
+	Customer  string  `json:"customer"`
+	StartDate string  `json:"startDate"` // YYYY-MM-DD
+	EndDate   string  `json:"endDate"`   // YYYY-MM-DD
+}
+
+// CampaignsResponse は外部API2のキャンペーン一覧レスポンスです
+type CampaignsResponse struct {
+	Campaigns     []CampaignPayload `json:"campaigns"`
+	NextPageToken string            `json:"nextPageToken"`
+}
+
+// ListCampaigns は指定された顧客IDのキャンペーン一覧を1ページ取得します
+// pageToken が空の場合は最初のページを取得します
+func (c *Client) ListCampaigns(ctx context.Context, customerID, pageToken string) (*CampaignsResponse, error) {
+	path := fmt.Sprintf("/customers/%s/campaigns", url.PathEscape(customerID))
+	if pageToken != "" {
+		path += "?pageToken=" + url.QueryEscape(pageToken)
+	}
+
+	resp, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result CampaignsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンスのデコードに失敗しました: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ListCampaigns はモックのキャンペーン一覧を1ページで返します
+func (m *MockClient) ListCampaigns(ctx context.Context, customerID, _ string) (*CampaignsResponse, error) {
+	data, err := m.GetCampaigns(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// モックデータを型付きのレスポンスに変換
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("モックデータのエンコードに失敗しました: %w", err)
+	}
+
+	var result CampaignsResponse
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		return nil, fmt.Errorf("モックデータのデコードに失敗しました: %w", err)
+	}
+
+	return &result, nil
+}
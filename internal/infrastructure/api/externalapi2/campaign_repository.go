@@ -0,0 +1,152 @@
+package externalapi2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// データ取得元
+const (
+	SourceMock = "mock" // モックデータを返す
+	SourceLive = "live" // 実際のAPIにHTTPリクエストを送信する
+)
+
+// campaignLister はキャンペーン一覧をページ単位で取得します
+type campaignLister interface {
+	ListCampaigns(ctx context.Context, customerID, pageToken string) (*CampaignsResponse, error)
+}
+
+// CampaignRepositoryImpl はExternalAPI2CampaignRepositoryインターフェースの実装です
+type CampaignRepositoryImpl struct {
+	client campaignLister
+}
+
+// NewCampaignRepository は設定されたデータ取得元（mock, live）に応じたExternalAPI2CampaignRepositoryを作成します
+func NewCampaignRepository(cfg *config.Config, client *Client) (repository.ExternalAPI2CampaignRepository, error) {
+	switch cfg.ExternalAPI2.Source {
+	case "", SourceMock:
+		log.Debug().Str("source", SourceMock).Msg("ExternalAPI2キャンペーンリポジトリのデータ取得元を設定しました")
+		return &CampaignRepositoryImpl{client: NewMockClient()}, nil
+	case SourceLive:
+		log.Debug().Str("source", SourceLive).Msg("ExternalAPI2キャンペーンリポジトリのデータ取得元を設定しました")
+		return &CampaignRepositoryImpl{client: client}, nil
+	default:
+		return nil, fmt.Errorf("未対応のExternalAPI2データ取得元です: %s（mock, live のいずれかを指定してください）", cfg.ExternalAPI2.Source)
+	}
+}
+
+// FetchCampaignsByAccountID は外部API2から指定されたアカウントIDに関連するキャンペーン情報を取得します
+func (r *CampaignRepositoryImpl) FetchCampaignsByAccountID(ctx context.Context, accountID uint, updatedSince *time.Time) ([]entity.Campaign, error) {
+	var campaigns []entity.Campaign
+	if _, err := r.StreamCampaignsByAccountID(ctx, accountID, updatedSince, func(page []entity.Campaign) error {
+		campaigns = append(campaigns, page...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// StreamCampaignsByAccountID は外部API2から指定されたアカウントIDに関連するキャンペーン情報をページ単位で取得し、
+// ページごとに handler を呼び出します
+// 外部API2はキャンペーンの更新日時を返さないため、updatedSince は使用せずに全件を取得します
+func (r *CampaignRepositoryImpl) StreamCampaignsByAccountID(ctx context.Context, accountID uint, _ *time.Time, handler func(campaigns []entity.Campaign) error) (int, error) {
+	customerID := strconv.FormatUint(uint64(accountID), 10)
+
+	pages := 0
+	pageToken := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return pages, fmt.Errorf("ページの取得を中断しました（%dページ読み込み済み）: %w", pages, err)
+		}
+
+		resp, err := r.client.ListCampaigns(ctx, customerID, pageToken)
+		if err != nil {
+			log.Error().Err(err).Uint("account_id", accountID).Int("pages", pages).Msg("キャンペーン情報の取得に失敗しました")
+			return pages, err
+		}
+		pages++
+
+		campaigns := make([]entity.Campaign, 0, len(resp.Campaigns))
+		for _, payload := range resp.Campaigns {
+			campaign, err := toCampaign(accountID, payload)
+			if err != nil {
+				return pages, err
+			}
+			campaigns = append(campaigns, campaign)
+		}
+
+		if err := handler(campaigns); err != nil {
+			return pages, err
+		}
+
+		if resp.NextPageToken == "" {
+			return pages, nil
+		}
+		if resp.NextPageToken == pageToken {
+			return pages, fmt.Errorf("次ページのトークンが現在のページと同じです: %s", pageToken)
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// toCampaign は外部API2のキャンペーン情報をキャンペーンエンティティに変換します
+func toCampaign(accountID uint, payload CampaignPayload) (entity.Campaign, error) {
+	id, err := strconv.ParseUint(payload.ID, 10, strconv.IntSize)
+	if err != nil {
+		return entity.Campaign{}, fmt.Errorf("キャンペーンIDの変換に失敗しました: %s: %w", payload.ID, err)
+	}
+
+	startDate, err := parseDate(payload.StartDate)
+	if err != nil {
+		return entity.Campaign{}, fmt.Errorf("キャンペーン %s の開始日の変換に失敗しました: %w", payload.ID, err)
+	}
+
+	endDate, err := parseDate(payload.EndDate)
+	if err != nil {
+		return entity.Campaign{}, fmt.Errorf("キャンペーン %s の終了日の変換に失敗しました: %w", payload.ID, err)
+	}
+
+	return entity.Campaign{
+		ID:        uint(id),
+		AccountID: accountID,
+		Source:    entity.SourceAPI2,
+		Name:      payload.Name,
+		Status:    toCampaignStatus(payload.Status),
+		Budget:    payload.Budget,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}, nil
+}
+
+// toCampaignStatus は外部API2のキャンペーンステータスを共通のステータスに変換します
+func toCampaignStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "ENABLED":
+		return "active"
+	case "PAUSED":
+		return "paused"
+	case "REMOVED":
+		return "removed"
+	default:
+		return strings.ToLower(status)
+	}
+}
+
+// parseDate は YYYY-MM-DD 形式の日付を変換します（空の場合はゼロ値を返します）
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
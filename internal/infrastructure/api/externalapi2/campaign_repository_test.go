@@ -0,0 +1,80 @@
+package externalapi2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// pagedLister はページトークンごとに決まったレスポンスを返す campaignLister です
+type pagedLister struct {
+	pages map[string]*CampaignsResponse
+}
+
+func (l *pagedLister) ListCampaigns(_ context.Context, _ string, pageToken string) (*CampaignsResponse, error) {
+	return l.pages[pageToken], nil
+}
+
+func TestStreamCampaignsByAccountID(t *testing.T) {
+	repo := &CampaignRepositoryImpl{client: &pagedLister{pages: map[string]*CampaignsResponse{
+		"": {
+			Campaigns:     []CampaignPayload{{ID: "111", Name: "A", Status: "ENABLED", Budget: 100, StartDate: "2024-01-01", EndDate: "2024-12-31"}},
+			NextPageToken: "page-2",
+		},
+		"page-2": {
+			Campaigns: []CampaignPayload{{ID: "222", Name: "B", Status: "PAUSED"}},
+		},
+	}}}
+
+	var campaigns []entity.Campaign
+	pages, err := repo.StreamCampaignsByAccountID(context.Background(), 42, nil, func(page []entity.Campaign) error {
+		campaigns = append(campaigns, page...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	assert.Len(t, campaigns, 2)
+
+	// 外部API2の形式からエンティティに変換されていること
+	assert.Equal(t, entity.Campaign{
+		ID:        111,
+		AccountID: 42,
+		Source:    entity.SourceAPI2,
+		Name:      "A",
+		Status:    "active",
+		Budget:    100,
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}, campaigns[0])
+	assert.Equal(t, "paused", campaigns[1].Status)
+}
+
+func TestToCampaignInvalidID(t *testing.T) {
+	_, err := toCampaign(1, CampaignPayload{ID: "campaign-12345"})
+	assert.Error(t, err)
+}
+
+func TestNewCampaignRepositoryMock(t *testing.T) {
+	repo, err := NewCampaignRepository(&config.Config{}, nil)
+	assert.NoError(t, err)
+
+	campaigns, err := repo.FetchCampaignsByAccountID(context.Background(), 1, nil)
+	assert.NoError(t, err)
+	assert.Len(t, campaigns, 2)
+
+	// 顧客ごとに異なるキャンペーンIDを返すこと
+	other, err := repo.FetchCampaignsByAccountID(context.Background(), 2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{101, 102}, []uint{campaigns[0].ID, campaigns[1].ID})
+	assert.Equal(t, []uint{201, 202}, []uint{other[0].ID, other[1].ID})
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.Source = "unknown"
+	_, err = NewCampaignRepository(cfg, nil)
+	assert.Error(t, err)
+}
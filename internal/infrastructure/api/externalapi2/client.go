@@ -3,16 +3,19 @@ package externalapi2
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+	httpClient "github.com/yuru-sha/go-cli-ddd/internal/infrastructure/http"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/secrets"
 )
 
@@ -22,9 +25,15 @@ type Client struct {
 	config         *config.Config
 	secretsManager secrets.Manager
 	oauthConfig    *oauth2.Config
-	tokenSource    oauth2.TokenSource
+	tokenStore     TokenStore
+
+	mu          sync.Mutex
+	tokenSource oauth2.TokenSource
 }
 
+// ErrRefreshTokenRevoked はリフレッシュトークンが失効または取り消されていることを表します
+var ErrRefreshTokenRevoked = errors.New("リフレッシュトークンが失効または取り消されています。再認証してリフレッシュトークンを更新してください")
+
 // OAuth2Secret はOAuth2認証情報を表します
 type OAuth2Secret struct {
 	ClientID     string `json:"client_id"`
@@ -33,7 +42,8 @@ type OAuth2Secret struct {
 }
 
 // NewClient は新しいClient インスタンスを作成します
-func NewClient(cfg *config.Config, httpClient *http.Client, secretsManager secrets.Manager) *Client {
+// tokenStore が nil の場合、アクセストークンはプロセス内でのみ保持されます
+func NewClient(cfg *config.Config, httpClient *http.Client, secretsManager secrets.Manager, tokenStore TokenStore) *Client {
 	if httpClient == nil {
 		httpClient = &http.Client{
 			Timeout: 30 * time.Second,
@@ -58,6 +68,7 @@ func NewClient(cfg *config.Config, httpClient *http.Client, secretsManager secre
 		config:         cfg,
 		secretsManager: secretsManager,
 		oauthConfig:    oauthConfig,
+		tokenStore:     tokenStore,
 	}
 
 	return client
@@ -65,6 +76,9 @@ func NewClient(cfg *config.Config, httpClient *http.Client, secretsManager secre
 
 // GetTokenSource はOAuth2トークンソースを取得します
 func (c *Client) GetTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// すでにトークンソースが初期化されている場合はそれを返す
 	if c.tokenSource != nil {
 		return c.tokenSource, nil
@@ -74,16 +88,9 @@ func (c *Client) GetTokenSource(ctx context.Context) (oauth2.TokenSource, error)
 
 	// Secret Managerからトークンを取得
 	if c.config.AWS.Secrets.Enabled && c.config.ExternalAPI2.OAuth2SecretID != "" {
-		log.Info().Msg("Secret ManagerからOAuth2認証情報を取得します")
-
-		secretValue, err := c.secretsManager.GetSecret(ctx, c.config.ExternalAPI2.OAuth2SecretID)
+		oauthSecret, err := c.loadOAuth2Secret(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("OAuth2認証情報の取得に失敗しました: %w", err)
-		}
-
-		var oauthSecret OAuth2Secret
-		if err := json.Unmarshal([]byte(secretValue), &oauthSecret); err != nil {
-			return nil, fmt.Errorf("OAuth2認証情報の解析に失敗しました: %w", err)
+			return nil, err
 		}
 
 		// Secret Managerから取得した値で設定を上書き
@@ -109,33 +116,123 @@ func (c *Client) GetTokenSource(ctx context.Context) (oauth2.TokenSource, error)
 	token := &oauth2.Token{
 		RefreshToken: refreshToken,
 	}
+	hash := refreshTokenHash(refreshToken)
+
+	// 以前の実行で保存したアクセストークンが同じリフレッシュトークンから発行されたものであれば再利用する
+	// 有効期限が切れている場合は oauth2 パッケージがリフレッシュする
+	// 保存先のキーには、Secret Managerによる上書き後の実際に使用するクライアントIDを使う
+	storeCtx := context.WithoutCancel(ctx)
+	clientID := c.oauthConfig.ClientID
+	if c.tokenStore != nil {
+		stored, err := c.tokenStore.Load(storeCtx, clientID)
+		switch {
+		case err != nil:
+			log.Warn().Err(err).Msg("保存されたOAuth2トークンの読み込みに失敗しました")
+		case stored != nil && stored.RefreshTokenHash == hash:
+			log.Debug().Time("expiry", stored.Expiry).Msg("保存されたOAuth2トークンを使用します")
+			token.AccessToken = stored.AccessToken
+			token.TokenType = stored.TokenType
+			token.Expiry = stored.Expiry
+		}
+	}
 
-	// トークンソースを作成して保存
-	c.tokenSource = c.oauthConfig.TokenSource(ctx, token)
+	// トークンソースを作成して保存（トークンの更新にも注入されたHTTPクライアントを使用）
+	// トークンソースは以降の呼び出しでも再利用するため、最初の呼び出し元のキャンセルに影響されないコンテキストで作成する
+	c.tokenSource = &storedTokenSource{
+		ctx:              storeCtx,
+		base:             c.oauthConfig.TokenSource(c.withHTTPClient(storeCtx), token),
+		store:            c.tokenStore,
+		clientID:         clientID,
+		refreshTokenHash: hash,
+		lastAccessToken:  token.AccessToken,
+	}
 	return c.tokenSource, nil
 }
 
+// loadOAuth2Secret はSecret ManagerからOAuth2認証情報を取得します
+func (c *Client) loadOAuth2Secret(ctx context.Context) (*OAuth2Secret, error) {
+	log.Info().Msg("Secret ManagerからOAuth2認証情報を取得します")
+
+	secretValue, err := c.secretsManager.GetSecret(ctx, c.config.ExternalAPI2.OAuth2SecretID)
+	if err != nil {
+		return nil, fmt.Errorf("OAuth2認証情報の取得に失敗しました: %w", err)
+	}
+
+	var oauthSecret OAuth2Secret
+	if err := json.Unmarshal([]byte(secretValue), &oauthSecret); err != nil {
+		return nil, fmt.Errorf("OAuth2認証情報の解析に失敗しました: %w", err)
+	}
+	return &oauthSecret, nil
+}
+
 // GetAuthenticatedClient は認証済みのHTTPクライアントを取得します
 func (c *Client) GetAuthenticatedClient(ctx context.Context) (*http.Client, error) {
+	return c.authenticatedClient(ctx, c.httpClient.Timeout)
+}
+
+// authenticatedClient は制限時間を timeout とした認証済みのHTTPクライアントを取得します（0の場合は制限しません）
+func (c *Client) authenticatedClient(ctx context.Context, timeout time.Duration) (*http.Client, error) {
 	tokenSource, err := c.GetTokenSource(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// OAuth2認証済みのHTTPクライアントを作成
-	return oauth2.NewClient(ctx, tokenSource), nil
+	// OAuth2認証済みのHTTPクライアントを作成（注入されたHTTPクライアントのTransportを使用）
+	client := oauth2.NewClient(c.withHTTPClient(ctx), tokenSource)
+	client.Timeout = timeout
+	return client, nil
+}
+
+// withHTTPClient はoauth2パッケージが注入されたHTTPクライアントを使用するようにコンテキストを設定します
+func (c *Client) withHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+}
+
+// errResponseHeaderTimeout はストリーミングのリクエストでレスポンスヘッダーを制限時間内に受信できなかったことを表します
+var errResponseHeaderTimeout = errors.New("レスポンスヘッダーの受信がタイムアウトしました")
+
+// requestOptions は外部API2へのリクエストのオプションです
+type requestOptions struct {
+	// idempotent が true の場合は、POSTでもサーバーエラー時にリトライできるよう冪等なリクエストとして送信します
+	idempotent bool
+
+	// stream が true の場合は、レスポンスボディを読み込む時間を制限しません
+	// http.Client の Timeout はボディの読み込みも含むため、レスポンスヘッダーの受信までの制限時間として適用し、
+	// 以降は呼び出し元のコンテキストの期限に従います
+	stream bool
 }
 
 // Request は外部API2へのリクエストを実行します
 func (c *Client) Request(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.request(ctx, method, path, body, requestOptions{})
+}
+
+// request は外部API2へのリクエストを実行します
+func (c *Client) request(ctx context.Context, method, path string, body io.Reader, opts requestOptions) (*http.Response, error) {
 	url := fmt.Sprintf("%s%s", c.config.ExternalAPI2.BaseURL, path)
 
 	// 認証済みのHTTPクライアントを取得
-	client, err := c.GetAuthenticatedClient(ctx)
+	timeout := c.httpClient.Timeout
+	if opts.stream {
+		timeout = 0
+	}
+	client, err := c.authenticatedClient(ctx, timeout)
 	if err != nil {
 		return nil, err
 	}
 
+	// ストリーミングではレスポンスヘッダーの受信までを制限時間とし、受信後はレスポンスボディを閉じるまでキャンセルしない
+	var cancel context.CancelCauseFunc
+	var headerTimer *time.Timer
+	if opts.stream && c.httpClient.Timeout > 0 {
+		ctx, cancel = context.WithCancelCause(ctx)
+		headerTimer = time.AfterFunc(c.httpClient.Timeout, func() { cancel(errResponseHeaderTimeout) })
+	}
+
+	if opts.idempotent {
+		ctx = httpClient.WithIdempotent(ctx)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("リクエストの作成に失敗しました: %w", err)
@@ -153,7 +250,22 @@ func (c *Client) Request(ctx context.Context, method, path string, body io.Reade
 
 	// リクエストを実行
 	resp, err := client.Do(req)
+	if cancel != nil {
+		headerTimer.Stop()
+		if err != nil {
+			if cause := context.Cause(ctx); errors.Is(cause, errResponseHeaderTimeout) {
+				err = cause
+			}
+			cancel(nil)
+		} else {
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		}
+	}
 	if err != nil {
+		if errors.Is(err, ErrRefreshTokenRevoked) {
+			log.Error().Err(err).Msg("ExternalAPI2の認証に失敗しました")
+			return nil, fmt.Errorf("ExternalAPI2の認証に失敗しました: %w", ErrRefreshTokenRevoked)
+		}
 		return nil, fmt.Errorf("リクエストの実行に失敗しました: %w", err)
 	}
 
@@ -207,3 +319,16 @@ func (c *Client) CreateCampaign(ctx context.Context, customerID string, campaign
 
 	return result, nil
 }
+
+// cancelOnClose はレスポンスボディを閉じた時点でリクエストのコンテキストをキャンセルします
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelCauseFunc
+}
+
+// Close はレスポンスボディを閉じ、リクエストのコンテキストを解放します
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel(nil)
+	return err
+}
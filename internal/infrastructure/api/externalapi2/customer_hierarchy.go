@@ -0,0 +1,120 @@
+package externalapi2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// customerClientQuery はマネージャーアカウント直下（level 1）の顧客を取得するクエリです
+const customerClientQuery = `SELECT customer_client.client_customer, customer_client.id, customer_client.descriptive_name, ` +
+	`customer_client.manager, customer_client.status, customer_client.level ` +
+	`FROM customer_client WHERE customer_client.level = 1`
+
+// CustomerClient はマネージャーアカウント配下の顧客アカウントです
+type CustomerClient struct {
+	CustomerID string // 顧客ID（ハイフンなし）
+	ParentID   string // 親のマネージャーアカウントの顧客ID
+	Name       string // アカウント名
+	Status     string // ENABLED, CANCELED, SUSPENDED, CLOSED など
+	Manager    bool   // マネージャーアカウントかどうか
+	Depth      int    // ルートのマネージャーアカウントからの深さ（直下が1）
+}
+
+// customerClientRow は customer_client クエリの結果行です
+type customerClientRow struct {
+	CustomerClient struct {
+		ClientCustomer  string `json:"clientCustomer"` // customers/1234567890
+		ID              string `json:"id"`
+		DescriptiveName string `json:"descriptiveName"`
+		Manager         bool   `json:"manager"`
+		Status          string `json:"status"`
+	} `json:"customerClient"`
+}
+
+// searchStreamer は searchStream クエリを実行します
+type searchStreamer interface {
+	SearchStream(ctx context.Context, customerID, query string, handler func(row json.RawMessage) error) (*SearchStreamResult, error)
+}
+
+// ListCustomerHierarchy はマネージャーアカウントから配下のサブマネージャー・顧客アカウントを再帰的にたどり、
+// 見つかった全てのアカウントを返します
+func (c *Client) ListCustomerHierarchy(ctx context.Context, managerCustomerID string) ([]CustomerClient, error) {
+	return walkCustomerHierarchy(ctx, c, managerCustomerID)
+}
+
+// walkCustomerHierarchy はマネージャーアカウントを幅優先でたどり、配下の全てのアカウントを返します
+// 同じアカウントが複数のマネージャーに紐づいている場合は最初に見つかったものだけを返します
+func walkCustomerHierarchy(ctx context.Context, s searchStreamer, rootCustomerID string) ([]CustomerClient, error) {
+	root := NormalizeCustomerID(rootCustomerID)
+	if root == "" {
+		return nil, fmt.Errorf("マネージャーアカウントの顧客IDが指定されていません")
+	}
+
+	type node struct {
+		customerID string
+		depth      int
+	}
+
+	visited := map[string]struct{}{root: {}}
+	queue := []node{{customerID: root}}
+	var clients []CustomerClient
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("アカウント階層の取得を中断しました: %w", err)
+		}
+
+		_, err := s.SearchStream(ctx, current.customerID, customerClientQuery, func(raw json.RawMessage) error {
+			var row customerClientRow
+			if err := json.Unmarshal(raw, &row); err != nil {
+				return fmt.Errorf("顧客情報のデコードに失敗しました: %w", err)
+			}
+
+			customerID := row.CustomerClient.ID
+			if customerID == "" {
+				customerID = strings.TrimPrefix(row.CustomerClient.ClientCustomer, "customers/")
+			}
+			customerID = NormalizeCustomerID(customerID)
+
+			if _, ok := visited[customerID]; ok {
+				return nil
+			}
+			visited[customerID] = struct{}{}
+
+			client := CustomerClient{
+				CustomerID: customerID,
+				ParentID:   current.customerID,
+				Name:       row.CustomerClient.DescriptiveName,
+				Status:     row.CustomerClient.Status,
+				Manager:    row.CustomerClient.Manager,
+				Depth:      current.depth + 1,
+			}
+			clients = append(clients, client)
+
+			// サブマネージャーの場合は配下もたどる
+			if client.Manager {
+				queue = append(queue, node{customerID: customerID, depth: client.Depth})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("顧客 %s の配下アカウントの取得に失敗しました: %w", current.customerID, err)
+		}
+
+		log.Debug().Str("customer_id", current.customerID).Int("depth", current.depth).Int("found", len(clients)).Msg("マネージャーアカウント配下のアカウントを取得しました")
+	}
+
+	return clients, nil
+}
+
+// NormalizeCustomerID は顧客IDからハイフンと空白を取り除きます（例: 123-456-7890 → 1234567890）
+func NormalizeCustomerID(customerID string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(customerID))
+}
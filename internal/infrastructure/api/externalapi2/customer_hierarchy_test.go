@@ -0,0 +1,62 @@
+package externalapi2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+func TestWalkCustomerHierarchy(t *testing.T) {
+	// 100 → 200(サブマネージャー) → 300(サブマネージャー) の3階層
+	// 顧客 11 は 100 と 200 の両方に紐づいている
+	client := NewMockClient()
+	client.SetMockData("searchStream:100", []map[string]interface{}{
+		mockCustomerClientRow("11", "顧客11", "ENABLED", false),
+		mockCustomerClientRow("200", "サブマネージャー200", "ENABLED", true),
+	})
+	client.SetMockData("searchStream:200", []map[string]interface{}{
+		mockCustomerClientRow("11", "顧客11", "ENABLED", false),
+		mockCustomerClientRow("21", "顧客21", "ENABLED", false),
+		mockCustomerClientRow("300", "サブマネージャー300", "ENABLED", true),
+	})
+	client.SetMockData("searchStream:300", []map[string]interface{}{
+		mockCustomerClientRow("31", "顧客31", "SUSPENDED", false),
+	})
+
+	clients, err := walkCustomerHierarchy(context.Background(), client, "1-0-0")
+	assert.NoError(t, err)
+
+	var ids []string
+	for _, c := range clients {
+		ids = append(ids, c.CustomerID)
+	}
+	assert.Equal(t, []string{"11", "200", "21", "300", "31"}, ids)
+	assert.Equal(t, "300", clients[4].ParentID)
+	assert.Equal(t, 3, clients[4].Depth)
+}
+
+func TestAccountRepositoryMockHierarchy(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.Source = SourceMock
+	repo, err := NewAccountRepository(cfg, nil)
+	assert.NoError(t, err)
+
+	accounts, err := repo.FetchAccounts(context.Background())
+	assert.NoError(t, err)
+
+	// サブマネージャーは含まず、配下の顧客アカウントは含む
+	assert.Equal(t, []entity.Account{
+		{ID: 2000000001, Source: entity.SourceAPI2, Name: "モック顧客アカウント1", Status: "active"},
+		{ID: 2000000002, Source: entity.SourceAPI2, Name: "モック顧客アカウント2", Status: "inactive"},
+		{ID: 2000000003, Source: entity.SourceAPI2, Name: "モック顧客アカウント3", Status: "active"},
+	}, accounts)
+}
+
+func TestWalkCustomerHierarchyEmptyRoot(t *testing.T) {
+	_, err := walkCustomerHierarchy(context.Background(), NewMockClient(), "")
+	assert.Error(t, err)
+}
@@ -103,7 +103,7 @@ func TestClientConfig(t *testing.T) {
 
 	// HTTPクライアントとシークレットマネージャーは実際のテストでは適切なものを使用する
 	// ここではnilを渡して、実際のAPIを呼び出さないようにする
-	client := NewClient(cfg, nil, nil)
+	client := NewClient(cfg, nil, nil, nil)
 
 	// クライアントの設定が正しく行われていることを確認
 	assert.Equal(t, "https://googleads.googleapis.com/v14", client.config.ExternalAPI2.BaseURL)
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
 	"golang.org/x/oauth2"
 )
 
 // MockClient は外部API2のモッククライアントです
+// 複数のゴルーチンから安全に利用できます
 type MockClient struct {
+	mu sync.Mutex
 	// モックデータを保持するマップ
 	mockData map[string]interface{}
 	// 呼び出されたメソッドを記録
@@ -30,16 +33,22 @@ func NewMockClient() *MockClient {
 
 // SetMockData はモックデータを設定します
 func (m *MockClient) SetMockData(key string, data interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.mockData[key] = data
 }
 
 // GetCalls は呼び出されたメソッドのリストを返します
 func (m *MockClient) GetCalls() []string {
-	return m.calls
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.calls...)
 }
 
 // recordCall はメソッド呼び出しを記録します
 func (m *MockClient) recordCall(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.calls = append(m.calls, method)
 }
 
@@ -73,17 +82,21 @@ func (m *MockClient) GetCampaigns(_ context.Context, customerID string) (map[str
 	m.recordCall(fmt.Sprintf("GetCampaigns:%s", customerID))
 
 	// モックデータが設定されている場合はそれを返す
-	if data, ok := m.mockData[fmt.Sprintf("campaigns:%s", customerID)]; ok {
+	m.mu.Lock()
+	data, ok := m.mockData[fmt.Sprintf("campaigns:%s", customerID)]
+	m.mu.Unlock()
+	if ok {
 		if result, ok := data.(map[string]interface{}); ok {
 			return result, nil
 		}
 	}
 
 	// デフォルトのモックデータを返す
+	// キャンペーンは (source, id) で保存されるため、顧客ごとに異なるIDになるよう顧客IDから生成する
 	return map[string]interface{}{
 		"campaigns": []map[string]interface{}{
 			{
-				"id":        "1234567890",
+				"id":        customerID + "01",
 				"name":      "Mock Campaign 1",
 				"status":    "ENABLED",
 				"budget":    100.00,
@@ -92,7 +105,7 @@ func (m *MockClient) GetCampaigns(_ context.Context, customerID string) (map[str
 				"endDate":   "2023-12-31",
 			},
 			{
-				"id":        "0987654321",
+				"id":        customerID + "02",
 				"name":      "Mock Campaign 2",
 				"status":    "PAUSED",
 				"budget":    200.00,
@@ -130,6 +143,30 @@ func (m *MockClient) CreateCampaign(_ context.Context, customerID string, campai
 	}, nil
 }
 
+// SearchStream は SetMockData("searchStream:<顧客ID>", []map[string]interface{}) で設定された行を handler に渡します
+func (m *MockClient) SearchStream(_ context.Context, customerID, _ string, handler func(row json.RawMessage) error) (*SearchStreamResult, error) {
+	m.recordCall(fmt.Sprintf("SearchStream:%s", customerID))
+
+	m.mu.Lock()
+	data := m.mockData[fmt.Sprintf("searchStream:%s", customerID)]
+	m.mu.Unlock()
+
+	result := &SearchStreamResult{Batches: 1}
+	rows, _ := data.([]map[string]interface{})
+	for _, row := range rows {
+		raw, err := json.Marshal(row)
+		if err != nil {
+			return result, fmt.Errorf("モックデータのエンコードに失敗しました: %w", err)
+		}
+		if err := handler(raw); err != nil {
+			return result, err
+		}
+		result.Rows++
+	}
+
+	return result, nil
+}
+
 // Request はモックリクエストを実行します（実際には何もしません）
 func (m *MockClient) Request(_ context.Context, method, path string, _ io.Reader) (*http.Response, error) {
 	m.recordCall(fmt.Sprintf("Request:%s:%s", method, path))
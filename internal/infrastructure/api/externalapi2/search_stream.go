@@ -0,0 +1,143 @@
+package externalapi2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// SearchStreamResult は searchStream の実行結果の概要です
+type SearchStreamResult struct {
+	Rows       int      // handler に渡した行数
+	Batches    int      // 読み込んだバッチ数
+	RequestIDs []string // バッチごとのリクエストID（問い合わせ用）
+}
+
+// searchStreamRequest は searchStream のリクエストボディです
+type searchStreamRequest struct {
+	Query string `json:"query"`
+}
+
+// SearchStream はクエリを送信し、ストリーミングで返される結果を1行ずつ handler に渡します
+// レスポンス全体をメモリに保持せず、バッチの配列を逐次デコードします
+// handler がエラーを返した場合は読み込みを中断してそのエラーを返します
+func (c *Client) SearchStream(ctx context.Context, customerID, query string, handler func(row json.RawMessage) error) (*SearchStreamResult, error) {
+	body, err := json.Marshal(searchStreamRequest{Query: query})
+	if err != nil {
+		return nil, fmt.Errorf("クエリのエンコードに失敗しました: %w", err)
+	}
+
+	path := fmt.Sprintf("/customers/%s/googleAds:searchStream", url.PathEscape(customerID))
+	// searchStream は参照系のため、POSTでもサーバーエラー時にリトライする
+	// 結果を逐次読み込むため、レスポンスボディの読み込みには http.Client の Timeout を適用しない
+	resp, err := c.request(ctx, http.MethodPost, path, bytes.NewReader(body), requestOptions{idempotent: true, stream: true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeSearchStream(resp.Body, handler)
+}
+
+// SearchStreamAs はクエリを送信し、結果の各行を T 型にデコードして handler に渡します
+func SearchStreamAs[T any](ctx context.Context, c *Client, customerID, query string, handler func(row T) error) (*SearchStreamResult, error) {
+	return c.SearchStream(ctx, customerID, query, func(raw json.RawMessage) error {
+		var row T
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return fmt.Errorf("行のデコードに失敗しました: %w", err)
+		}
+		return handler(row)
+	})
+}
+
+// decodeSearchStream は searchStream のレスポンス（バッチの配列）を逐次デコードし、各行を handler に渡します
+// レスポンスの形式: [{"results": [{...}, ...], "fieldMask": "...", "requestId": "..."}, ...]
+func decodeSearchStream(r io.Reader, handler func(row json.RawMessage) error) (*SearchStreamResult, error) {
+	dec := json.NewDecoder(r)
+	result := &SearchStreamResult{}
+
+	if err := expectDelim(dec, '['); err != nil {
+		return result, err
+	}
+
+	for dec.More() {
+		if err := decodeSearchStreamBatch(dec, result, handler); err != nil {
+			return result, err
+		}
+		result.Batches++
+	}
+
+	if err := expectDelim(dec, ']'); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// decodeSearchStreamBatch は1バッチ分のオブジェクトを逐次デコードします
+func decodeSearchStreamBatch(dec *json.Decoder, result *SearchStreamResult, handler func(row json.RawMessage) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("レスポンスの読み込みに失敗しました: %w", err)
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("レスポンスの形式が不正です: キーが文字列ではありません: %v", token)
+		}
+
+		switch key {
+		case "results":
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				var row json.RawMessage
+				if err := dec.Decode(&row); err != nil {
+					return fmt.Errorf("行の読み込みに失敗しました: %w", err)
+				}
+				if err := handler(row); err != nil {
+					return err
+				}
+				result.Rows++
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		case "requestId":
+			var requestID string
+			if err := dec.Decode(&requestID); err != nil {
+				return fmt.Errorf("リクエストIDの読み込みに失敗しました: %w", err)
+			}
+			result.RequestIDs = append(result.RequestIDs, requestID)
+		default:
+			// fieldMask などその他のフィールドは読み飛ばす
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("レスポンスの読み込みに失敗しました: %w", err)
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// expectDelim は次のトークンが指定された区切り文字であることを確認します
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("レスポンスの読み込みに失敗しました: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("レスポンスの形式が不正です: %q を期待しましたが %v でした", want, token)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package externalapi2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+	httpClient "github.com/yuru-sha/go-cli-ddd/internal/infrastructure/http"
+)
+
+const searchStreamResponse = `[
+  {"results": [{"campaign": {"id": "1", "name": "A"}}, {"campaign": {"id": "2", "name": "B"}}], "fieldMask": "campaign.id,campaign.name", "requestId": "req-1"},
+  {"results": [{"campaign": {"id": "3", "name": "C"}}], "fieldMask": "campaign.id,campaign.name", "requestId": "req-2"}
+]`
+
+type campaignRow struct {
+	Campaign struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"campaign"`
+}
+
+func TestSearchStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/customers/123/googleAds:searchStream", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var body searchStreamRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "SELECT campaign.id, campaign.name FROM campaign", body.Query)
+
+		_, _ = w.Write([]byte(searchStreamResponse))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.BaseURL = server.URL
+	client := NewClient(cfg, nil, nil, nil)
+	client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+
+	var names []string
+	result, err := SearchStreamAs(context.Background(), client, "123", "SELECT campaign.id, campaign.name FROM campaign", func(row campaignRow) error {
+		names = append(names, row.Campaign.Name)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "B", "C"}, names)
+	assert.Equal(t, 3, result.Rows)
+	assert.Equal(t, 2, result.Batches)
+	assert.Equal(t, []string{"req-1", "req-2"}, result.RequestIDs)
+}
+
+func TestSearchStreamRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 冪等であることはヘッダーではなくコンテキストで伝える
+		_, ok := r.Header["Idempotency-Key"]
+		assert.False(t, ok)
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(searchStreamResponse))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.BaseURL = server.URL
+	client := NewClient(cfg, &http.Client{Transport: httpClient.NewRetryTransport(nil, nil, 3)}, nil, nil)
+	client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+
+	// searchStream は参照系のため、POSTでもサーバーエラー時にリトライする
+	result, err := client.SearchStream(context.Background(), "123", "SELECT campaign.id FROM campaign", func(json.RawMessage) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Rows)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestSearchStreamOutlivesClientTimeout(t *testing.T) {
+	batches := strings.SplitAfter(searchStreamResponse, "\"requestId\": \"req-1\"},")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/customers/slow-header/googleAds:searchStream" {
+			time.Sleep(300 * time.Millisecond)
+		}
+		// 1バッチ目を送信した後、HTTPクライアントの制限時間より長く待ってから残りを送信する
+		_, _ = w.Write([]byte(batches[0]))
+		w.(http.Flusher).Flush()
+		time.Sleep(300 * time.Millisecond)
+		_, _ = w.Write([]byte(batches[1]))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.BaseURL = server.URL
+	client := NewClient(cfg, &http.Client{Timeout: 100 * time.Millisecond}, nil, nil)
+	client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+
+	// レスポンスボディの読み込みには制限時間を適用しない
+	result, err := client.SearchStream(context.Background(), "123", "SELECT campaign.id FROM campaign", func(json.RawMessage) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Rows)
+
+	// レスポンスヘッダーの受信には制限時間を適用する
+	_, err = client.SearchStream(context.Background(), "slow-header", "SELECT campaign.id FROM campaign", func(json.RawMessage) error { return nil })
+	assert.ErrorIs(t, err, errResponseHeaderTimeout)
+
+	// 呼び出し元のコンテキストの期限には従う
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = client.SearchStream(ctx, "123", "SELECT campaign.id FROM campaign", func(json.RawMessage) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDecodeSearchStreamStopsOnHandlerError(t *testing.T) {
+	errStop := errors.New("stop")
+
+	rows := 0
+	result, err := decodeSearchStream(strings.NewReader(searchStreamResponse), func(_ json.RawMessage) error {
+		rows++
+		if rows == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, result.Rows)
+}
+
+func TestDecodeSearchStreamInvalidResponse(t *testing.T) {
+	_, err := decodeSearchStream(strings.NewReader(`{"error": "invalid"}`), func(_ json.RawMessage) error { return nil })
+	assert.Error(t, err)
+
+	// 途中で切れたレスポンス
+	_, err = decodeSearchStream(strings.NewReader(`[{"results": [{"id": 1}`), func(_ json.RawMessage) error { return nil })
+	assert.Error(t, err)
+}
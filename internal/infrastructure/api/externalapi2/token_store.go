@@ -0,0 +1,370 @@
+package externalapi2
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/persistence/dynamodb"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/secrets"
+)
+
+// トークンの保存先
+const (
+	TokenStoreMemory   = "memory"   // 保存しない（プロセス内のみ）
+	TokenStoreFile     = "file"     // 暗号化したローカルファイル
+	TokenStoreDynamoDB = "dynamodb" // DynamoDB
+)
+
+// tokenStorePartitionKey はDynamoDBに保存するトークンのパーティションキーです
+const tokenStorePartitionKey = "externalapi2#oauth2_token"
+
+// StoredToken は保存されたアクセストークンです
+// リフレッシュトークン自体は保存せず、どのリフレッシュトークンから発行されたかをハッシュで記録します
+type StoredToken struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	Expiry           time.Time `json:"expiry"`
+	RefreshTokenHash string    `json:"refresh_token_hash"`
+}
+
+// TokenStore はアクセストークンを短命なCLIの実行をまたいで保存します
+// clientID にはトークンの発行に実際に使用したOAuth2クライアントIDを指定します
+type TokenStore interface {
+	// Load は保存されたトークンを取得します（保存されていない場合は nil を返します）
+	Load(ctx context.Context, clientID string) (*StoredToken, error)
+
+	// Save はトークンを保存します
+	Save(ctx context.Context, clientID string, token *StoredToken) error
+
+	// Delete は保存されたトークンを削除します
+	Delete(ctx context.Context, clientID string) error
+}
+
+// NewTokenStore は設定された保存先に応じたTokenStoreを作成します
+// 保存先が memory の場合は nil を返し、トークンはプロセス内でのみ保持されます
+func NewTokenStore(cfg *config.Config, secretsManager secrets.Manager) (TokenStore, error) {
+	storeCfg := cfg.ExternalAPI2.TokenStore
+
+	switch storeCfg.Type {
+	case "", TokenStoreMemory:
+		return nil, nil
+	case TokenStoreFile:
+		if storeCfg.FilePath == "" {
+			return nil, fmt.Errorf("トークンの保存先ファイルが設定されていません")
+		}
+		log.Debug().Str("type", TokenStoreFile).Str("path", storeCfg.FilePath).Msg("OAuth2トークンの保存先を設定しました")
+		return NewFileTokenStore(storeCfg.FilePath, func(ctx context.Context) (string, error) {
+			if cfg.AWS.Secrets.Enabled && storeCfg.EncryptionKeySecretID != "" {
+				return secretsManager.GetSecret(ctx, storeCfg.EncryptionKeySecretID)
+			}
+			return storeCfg.EncryptionKey, nil
+		}), nil
+	case TokenStoreDynamoDB:
+		if storeCfg.DynamoDBTable == "" {
+			return nil, fmt.Errorf("トークンの保存先テーブルが設定されていません")
+		}
+
+		var client dynamodb.Client
+		var err error
+		if storeCfg.DynamoDBEndpoint != "" {
+			client, err = dynamodb.NewLocalDynamoDBClient(context.Background(), storeCfg.DynamoDBEndpoint)
+		} else {
+			client, err = dynamodb.NewDynamoDBClient(context.Background(), cfg.AWS.Region)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("DynamoDBクライアントの作成に失敗しました: %w", err)
+		}
+
+		log.Debug().Str("type", TokenStoreDynamoDB).Str("table", storeCfg.DynamoDBTable).Msg("OAuth2トークンの保存先を設定しました")
+		return NewDynamoDBTokenStore(dynamodb.NewDynamoDBRepository(client, storeCfg.DynamoDBTable)), nil
+	default:
+		return nil, fmt.Errorf("未対応のトークン保存先です: %s（%s, %s, %s のいずれかを指定してください）",
+			storeCfg.Type, TokenStoreMemory, TokenStoreFile, TokenStoreDynamoDB)
+	}
+}
+
+// FileTokenStore はトークンをAES-GCMで暗号化してローカルファイルに保存します
+// ファイルは1つのOAuth2クライアントで使用する前提のため、クライアントIDは区別しません
+// （別のリフレッシュトークンから発行されたトークンはハッシュの比較で再利用されません）
+type FileTokenStore struct {
+	path      string
+	keySource func(ctx context.Context) (string, error)
+
+	mu  sync.Mutex
+	key []byte
+}
+
+// NewFileTokenStore は新しいFileTokenStoreを作成します
+// 暗号化キーは keySource から最初の読み書き時に取得します
+func NewFileTokenStore(path string, keySource func(ctx context.Context) (string, error)) *FileTokenStore {
+	return &FileTokenStore{path: path, keySource: keySource}
+}
+
+// Load は保存されたトークンを復号して取得します
+func (s *FileTokenStore) Load(ctx context.Context, _ string) (*StoredToken, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("トークンファイルの読み込みに失敗しました: %w", err)
+	}
+
+	gcm, err := s.cipher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("トークンファイルの形式が不正です: %s", s.path)
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("トークンファイルの復号に失敗しました（暗号化キーが変更された可能性があります）: %w", err)
+	}
+
+	var token StoredToken
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, fmt.Errorf("トークンの解析に失敗しました: %w", err)
+	}
+	return &token, nil
+}
+
+// Save はトークンを暗号化してファイルに保存します
+// 書き込み途中のファイルを読み込まないよう、一時ファイルに書き込んでから置き換えます
+func (s *FileTokenStore) Save(ctx context.Context, _ string, token *StoredToken) error {
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("トークンのエンコードに失敗しました: %w", err)
+	}
+
+	gcm, err := s.cipher(ctx)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("nonceの生成に失敗しました: %w", err)
+	}
+	data := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("トークンファイルのディレクトリ作成に失敗しました: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("一時ファイルの作成に失敗しました: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("トークンファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("トークンファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("トークンファイルの置き換えに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Delete はトークンファイルを削除します
+func (s *FileTokenStore) Delete(_ context.Context, _ string) error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("トークンファイルの削除に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// cipher は暗号化キーからAES-GCMを作成します
+// 任意の長さのキーを扱えるよう、SHA-256で256ビットのキーに変換します
+func (s *FileTokenStore) cipher(ctx context.Context) (cipher.AEAD, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		passphrase, err := s.keySource(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("トークンの暗号化キーの取得に失敗しました: %w", err)
+		}
+		if passphrase == "" {
+			return nil, fmt.Errorf("トークンの暗号化キーが設定されていません")
+		}
+		sum := sha256.Sum256([]byte(passphrase))
+		s.key = sum[:]
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, fmt.Errorf("暗号化の初期化に失敗しました: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("暗号化の初期化に失敗しました: %w", err)
+	}
+	return gcm, nil
+}
+
+// DynamoDBTokenStore はトークンをDynamoDBに保存します
+// 複数のOAuth2クライアントで同じテーブルを共有できるよう、ソートキーにクライアントIDを使用します
+type DynamoDBTokenStore struct {
+	repo repository.DynamoDBRepository
+}
+
+// NewDynamoDBTokenStore は新しいDynamoDBTokenStoreを作成します
+func NewDynamoDBTokenStore(repo repository.DynamoDBRepository) *DynamoDBTokenStore {
+	return &DynamoDBTokenStore{repo: repo}
+}
+
+// Load は保存されたトークンを取得します
+func (s *DynamoDBTokenStore) Load(ctx context.Context, clientID string) (*StoredToken, error) {
+	item, err := s.repo.GetItem(ctx, tokenStorePartitionKey, tokenSortKey(clientID))
+	if err != nil {
+		return nil, fmt.Errorf("トークンの取得に失敗しました: %w", err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+
+	token := &StoredToken{}
+	token.AccessToken, _ = item["access_token"].(string)
+	token.TokenType, _ = item["token_type"].(string)
+	token.RefreshTokenHash, _ = item["refresh_token_hash"].(string)
+	if expiry, ok := item["expiry"].(string); ok && expiry != "" {
+		if token.Expiry, err = time.Parse(time.RFC3339, expiry); err != nil {
+			return nil, fmt.Errorf("トークンの有効期限の解析に失敗しました: %w", err)
+		}
+	}
+	return token, nil
+}
+
+// Save はトークンを保存します
+func (s *DynamoDBTokenStore) Save(ctx context.Context, clientID string, token *StoredToken) error {
+	item := map[string]interface{}{
+		"PK":                 tokenStorePartitionKey,
+		"SK":                 tokenSortKey(clientID),
+		"access_token":       token.AccessToken,
+		"token_type":         token.TokenType,
+		"expiry":             token.Expiry.UTC().Format(time.RFC3339),
+		"refresh_token_hash": token.RefreshTokenHash,
+		"updated_at":         time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := s.repo.PutItem(ctx, item); err != nil {
+		return fmt.Errorf("トークンの保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Delete は保存されたトークンを削除します
+func (s *DynamoDBTokenStore) Delete(ctx context.Context, clientID string) error {
+	if err := s.repo.DeleteItem(ctx, tokenStorePartitionKey, tokenSortKey(clientID)); err != nil {
+		return fmt.Errorf("トークンの削除に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// tokenSortKey はクライアントIDからトークンのソートキーを返します
+func tokenSortKey(clientID string) string {
+	if clientID == "" {
+		return "default"
+	}
+	return clientID
+}
+
+// refreshTokenHash はリフレッシュトークンのハッシュを返します
+func refreshTokenHash(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// storedTokenSource はトークンの更新時に保存先へ書き込み、リフレッシュトークンの失効を検出するTokenSourceです
+type storedTokenSource struct {
+	ctx              context.Context
+	base             oauth2.TokenSource
+	store            TokenStore
+	clientID         string
+	refreshTokenHash string
+
+	mu              sync.Mutex
+	lastAccessToken string
+}
+
+// Token はアクセストークンを取得し、新しく発行された場合は保存先に書き込みます
+func (s *storedTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.store == nil || token.AccessToken == s.lastAccessToken {
+		return token, nil
+	}
+	s.lastAccessToken = token.AccessToken
+
+	// 保存に失敗しても今回の実行は継続できるため、警告のみ出力する
+	stored := &StoredToken{
+		AccessToken:      token.AccessToken,
+		TokenType:        token.TokenType,
+		Expiry:           token.Expiry,
+		RefreshTokenHash: s.refreshTokenHash,
+	}
+	if err := s.store.Save(s.ctx, s.clientID, stored); err != nil {
+		log.Warn().Err(err).Msg("OAuth2トークンの保存に失敗しました")
+	} else {
+		log.Debug().Time("expiry", token.Expiry).Msg("OAuth2トークンを保存しました")
+	}
+	return token, nil
+}
+
+// handleError はトークンの取得エラーを変換します
+// リフレッシュトークンが失効・取り消されている場合は保存されたトークンを削除し、ErrRefreshTokenRevoked を返します
+func (s *storedTokenSource) handleError(err error) error {
+	if !isRevokedTokenError(err) {
+		return err
+	}
+
+	if s.store != nil {
+		if deleteErr := s.store.Delete(s.ctx, s.clientID); deleteErr != nil {
+			log.Warn().Err(deleteErr).Msg("失効したOAuth2トークンの削除に失敗しました")
+		}
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.ErrorDescription != "" {
+		return fmt.Errorf("%w（%s）", ErrRefreshTokenRevoked, retrieveErr.ErrorDescription)
+	}
+	return ErrRefreshTokenRevoked
+}
+
+// isRevokedTokenError はリフレッシュトークンが失効・取り消されたことを示すエラーかどうかを判定します
+func isRevokedTokenError(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return false
+	}
+	return retrieveErr.ErrorCode == "invalid_grant"
+}
@@ -0,0 +1,239 @@
+package externalapi2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// memoryDynamoDBRepository はGetItem, PutItem, DeleteItem のみをメモリ上で実装したDynamoDBRepositoryです
+type memoryDynamoDBRepository struct {
+	repository.DynamoDBRepository
+	items map[string]map[string]interface{}
+}
+
+func (r *memoryDynamoDBRepository) GetItem(_ context.Context, pk, sk string) (map[string]interface{}, error) {
+	return r.items[pk+"/"+sk], nil
+}
+
+func (r *memoryDynamoDBRepository) PutItem(_ context.Context, item map[string]interface{}) error {
+	r.items[item["PK"].(string)+"/"+item["SK"].(string)] = item
+	return nil
+}
+
+func (r *memoryDynamoDBRepository) DeleteItem(_ context.Context, pk, sk string) error {
+	delete(r.items, pk+"/"+sk)
+	return nil
+}
+
+func staticKey(key string) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) { return key, nil }
+}
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.enc")
+	store := NewFileTokenStore(path, staticKey("secret"))
+
+	// 保存されていない場合は nil
+	token, err := store.Load(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	expiry := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Save(context.Background(), "", &StoredToken{AccessToken: "access-1", TokenType: "Bearer", Expiry: expiry, RefreshTokenHash: "hash"}))
+
+	// ファイルは暗号化されている
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "access-1")
+
+	token, err = store.Load(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.True(t, expiry.Equal(token.Expiry))
+
+	// 異なるキーでは復号できない
+	_, err = NewFileTokenStore(path, staticKey("other")).Load(context.Background(), "")
+	assert.Error(t, err)
+
+	assert.NoError(t, store.Delete(context.Background(), ""))
+	token, err = store.Load(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestDynamoDBTokenStore(t *testing.T) {
+	store := NewDynamoDBTokenStore(&memoryDynamoDBRepository{items: map[string]map[string]interface{}{}})
+
+	expiry := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Save(context.Background(), "client-1", &StoredToken{AccessToken: "access-1", TokenType: "Bearer", Expiry: expiry, RefreshTokenHash: "hash-1"}))
+	assert.NoError(t, store.Save(context.Background(), "client-2", &StoredToken{AccessToken: "access-2", TokenType: "Bearer", Expiry: expiry, RefreshTokenHash: "hash-2"}))
+
+	// クライアントIDごとに別のトークンとして保存される
+	token, err := store.Load(context.Background(), "client-1")
+	assert.NoError(t, err)
+	assert.Equal(t, &StoredToken{AccessToken: "access-1", TokenType: "Bearer", Expiry: expiry, RefreshTokenHash: "hash-1"}, token)
+
+	token, err = store.Load(context.Background(), "client-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-2", token.AccessToken)
+
+	assert.NoError(t, store.Delete(context.Background(), "client-2"))
+	token, err = store.Load(context.Background(), "client-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+}
+
+// newTokenServer は呼び出しごとに異なるアクセストークンを返すトークンエンドポイントを作成します
+func newTokenServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			n := atomic.AddInt32(calls, 1)
+			w.Header().Set("Content-Type", "application/json")
+			if r.FormValue("refresh_token") == "revoked" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "Token has been expired or revoked."}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"access_token": "access-%d", "token_type": "Bearer", "expires_in": 3600}`, n)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+}
+
+func newTokenStoreTestClient(serverURL, refreshToken string, store TokenStore) *Client {
+	cfg := &config.Config{}
+	cfg.ExternalAPI2.BaseURL = serverURL
+	cfg.ExternalAPI2.RefreshToken = refreshToken
+	client := NewClient(cfg, nil, nil, store)
+	client.oauthConfig.Endpoint.TokenURL = serverURL + "/token"
+	return client
+}
+
+func TestTokenStoreSurvivesClients(t *testing.T) {
+	var calls int32
+	server := newTokenServer(&calls)
+	defer server.Close()
+
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.enc"), staticKey("secret"))
+
+	// 1回目の実行ではトークンを発行して保存する
+	_, err := newTokenStoreTestClient(server.URL, "refresh-1", store).GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	stored, err := store.Load(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", stored.AccessToken)
+
+	// 2回目の実行では保存されたトークンを再利用する
+	_, err = newTokenStoreTestClient(server.URL, "refresh-1", store).GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// リフレッシュトークンが変わった場合は保存されたトークンを使わない
+	_, err = newTokenStoreTestClient(server.URL, "refresh-2", store).GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// staticSecretsManager はシークレットIDに対応する固定値を返すsecrets.Managerです
+type staticSecretsManager map[string]string
+
+func (m staticSecretsManager) GetSecret(_ context.Context, secretID string) (string, error) {
+	return m[secretID], nil
+}
+
+func TestTokenStoreKeyedBySecretClientID(t *testing.T) {
+	var calls int32
+	server := newTokenServer(&calls)
+	defer server.Close()
+
+	// 設定ファイルのクライアントIDは同じでも、Secret Managerから取得したクライアントIDごとにトークンを保存する
+	store := NewDynamoDBTokenStore(&memoryDynamoDBRepository{items: map[string]map[string]interface{}{}})
+	newClient := func(clientID, refreshToken string) *Client {
+		client := newTokenStoreTestClient(server.URL, "", store)
+		client.config.AWS.Secrets.Enabled = true
+		client.config.ExternalAPI2.OAuth2SecretID = "oauth2"
+		client.secretsManager = staticSecretsManager{
+			"oauth2": fmt.Sprintf(`{"client_id": %q, "refresh_token": %q}`, clientID, refreshToken),
+		}
+		return client
+	}
+
+	_, err := newClient("client-1", "refresh-1").GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	_, err = newClient("client-2", "refresh-2").GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// 別のクライアントIDのトークンで上書きされず、保存されたトークンを再利用する
+	_, err = newClient("client-1", "refresh-1").GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	_, err = newClient("client-2", "refresh-2").GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	stored, err := store.Load(context.Background(), "client-1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, stored) {
+		assert.Equal(t, refreshTokenHash("refresh-1"), stored.RefreshTokenHash)
+	}
+}
+
+func TestTokenRefreshAfterFirstContextCancelled(t *testing.T) {
+	var calls int32
+	// 有効期限が短いトークンを発行し、リクエストごとにトークンを更新させる
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			n := atomic.AddInt32(&calls, 1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"access_token": "access-%d", "token_type": "Bearer", "expires_in": 1}`, n)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTokenStoreTestClient(server.URL, "refresh-1", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := client.GetCampaigns(ctx, "1")
+	assert.NoError(t, err)
+	cancel()
+
+	// 最初の呼び出し元のコンテキストがキャンセルされても、以降の呼び出しでトークンを更新できること
+	_, err = client.GetCampaigns(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRevokedRefreshToken(t *testing.T) {
+	var calls int32
+	server := newTokenServer(&calls)
+	defer server.Close()
+
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.enc"), staticKey("secret"))
+	assert.NoError(t, store.Save(context.Background(), "", &StoredToken{AccessToken: "expired", Expiry: time.Now().Add(-time.Hour), RefreshTokenHash: refreshTokenHash("revoked")}))
+
+	_, err := newTokenStoreTestClient(server.URL, "revoked", store).GetCampaigns(context.Background(), "1")
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+
+	// 失効したトークンは削除される
+	stored, err := store.Load(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
@@ -33,6 +33,7 @@ type DatabaseConfig struct {
 	LogLevel    string       `mapstructure:"log_level"`
 	AutoMigrate bool         `mapstructure:"auto_migrate"`
 	SecretID    string       `mapstructure:"secret_id"`
+	BatchSize   int          `mapstructure:"batch_size"` // 一括保存で1回のSQLに含める件数（0の場合は500）
 	Aurora      AuroraConfig `mapstructure:"aurora"`
 }
 
@@ -82,6 +83,7 @@ type SlackConfig struct {
 	IconEmoji       string `mapstructure:"icon_emoji"`
 	SuccessEmoji    string `mapstructure:"success_emoji"`
 	FailureEmoji    string `mapstructure:"failure_emoji"`
+	CancelledEmoji  string `mapstructure:"cancelled_emoji"` // 中断時のアイコン（未設定の場合は failure_emoji）
 }
 
 // AWSConfig はAWS関連の設定です
@@ -99,6 +101,18 @@ type SecretsConfig struct {
 type ExternalAPI1Config struct {
 	BaseURL       string `mapstructure:"base_url"`
 	TokenSecretID string `mapstructure:"token_secret_id"`
+	Source        string `mapstructure:"source"`          // データ取得元（"mock", "live" または "fixture"）
+	FixtureDir    string `mapstructure:"fixture_dir"`     // source が "fixture" の場合に読み込むJSONファイルのディレクトリ
+	TokenCacheTTL int    `mapstructure:"token_cache_ttl"` // APIトークンをキャッシュする秒数（0の場合は300秒）
+
+	Pagination PaginationConfig `mapstructure:"pagination"`
+}
+
+// PaginationConfig は一覧取得APIのページネーション設定です
+type PaginationConfig struct {
+	Style    string `mapstructure:"style"`     // ページネーション方式（"none", "cursor", "page" または "offset"）
+	PageSize int    `mapstructure:"page_size"` // 1ページあたりの取得件数
+	MaxPages int    `mapstructure:"max_pages"` // 読み込むページ数の上限（0の場合は無制限）
 }
 
 // ExternalAPI2Config は外部API2（例：Google Ads API）の設定です
@@ -110,6 +124,19 @@ type ExternalAPI2Config struct {
 	OAuth2SecretID  string `mapstructure:"oauth2_secret_id"`
 	DeveloperToken  string `mapstructure:"developer_token"`
 	LoginCustomerID string `mapstructure:"login_customer_id"`
+	Source          string `mapstructure:"source"` // データ取得元（"mock" または "live"）
+
+	TokenStore TokenStoreConfig `mapstructure:"token_store"`
+}
+
+// TokenStoreConfig はOAuth2トークン（アクセストークン）の保存先の設定です
+type TokenStoreConfig struct {
+	Type                  string `mapstructure:"type"`                     // 保存先（"memory", "file" または "dynamodb"、空の場合は memory）
+	FilePath              string `mapstructure:"file_path"`                // type が "file" の場合の保存先ファイル
+	EncryptionKey         string `mapstructure:"encryption_key"`           // type が "file" の場合の暗号化キー
+	EncryptionKeySecretID string `mapstructure:"encryption_key_secret_id"` // 暗号化キーを取得するSecret ManagerのシークレットID（encryption_key より優先）
+	DynamoDBTable         string `mapstructure:"dynamodb_table"`           // type が "dynamodb" の場合のテーブル名
+	DynamoDBEndpoint      string `mapstructure:"dynamodb_endpoint"`        // ローカル開発用のDynamoDBエンドポイント（空の場合はAWS）
 }
 
 // Options は設定読み込みのオプションを表します
@@ -11,20 +11,33 @@ import (
 )
 
 // NewHTTPClient は設定に基づいてHTTPクライアントを作成します
+// レート制限とリトライ（429・5xx・ネットワークエラー）はTransportで行います
+// Timeout はリトライを含めたリクエスト全体の制限時間です
 func NewHTTPClient(cfg *config.HTTPConfig) *http.Client {
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 20,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
 	return &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 20,
-			IdleConnTimeout:     90 * time.Second,
-		},
+		Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		Transport: NewRetryTransport(transport, NewRateLimiter(&cfg.RateLimit), cfg.MaxRetries),
 	}
 }
 
 // NewRateLimiter はレート制限を行うリミッターを作成します
+// QPSが0以下の場合はレート制限を行いません
 func NewRateLimiter(cfg *config.RateLimitConfig) *rate.Limiter {
-	return rate.NewLimiter(rate.Limit(cfg.QPS), cfg.Burst)
+	if cfg.QPS <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+
+	burst := cfg.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(cfg.QPS), burst)
 }
 
 // NewBackOff はリトライ用のバックオフポリシーを作成します
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+// maxRetryAfter はRetry-Afterヘッダーで指定された待機時間の上限です
+const maxRetryAfter = 60 * time.Second
+
+// RetryTransport はレート制限とリトライを行うhttp.RoundTripperです
+// 429・5xxのレスポンスとネットワークエラーの場合にバックオフしながらリトライします
+// 冪等でないリクエスト（POSTなど）は、処理されずに拒否された429の場合のみリトライします
+// リクエストの制限時間内に次の送信ができない場合は、待機せずに最後のレスポンスを返します
+type RetryTransport struct {
+	base       http.RoundTripper
+	limiter    *rate.Limiter
+	newBackOff func() backoff.BackOff
+}
+
+// NewRetryTransport は新しいRetryTransportを作成します
+func NewRetryTransport(base http.RoundTripper, limiter *rate.Limiter, maxRetries int) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &RetryTransport{
+		base:    base,
+		limiter: limiter,
+		newBackOff: func() backoff.BackOff {
+			return NewBackOff(maxRetries)
+		},
+	}
+}
+
+// RoundTrip はリクエストを送信し、必要に応じてリトライします
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	b := backoff.WithContext(t.newBackOff(), ctx)
+
+	for attempt := 1; ; attempt++ {
+		// レート制限
+		if t.limiter != nil {
+			if err := t.limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("レート制限の待機中に中断しました: %w", err)
+			}
+		}
+
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if !shouldRetry(ctx, req, resp, err) || !canRetry(req) {
+			return resp, err
+		}
+
+		// 次の待機時間を決定（Retry-Afterヘッダーがある場合は優先）
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return resp, err
+		}
+		if retryAfter, ok := parseRetryAfter(resp); ok {
+			wait = retryAfter
+		}
+
+		// 待機している間に制限時間（http.Client の Timeout など）を過ぎる場合はリトライしない
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+			log.Warn().
+				Str("method", req.Method).
+				Str("url", req.URL.Redacted()).
+				Int("attempt", attempt).
+				Dur("wait", wait).
+				Msg("制限時間内にリトライできないため、リトライせずに終了します")
+			return resp, err
+		}
+
+		logEvent := log.Warn().
+			Str("method", req.Method).
+			Str("url", req.URL.Redacted()).
+			Int("attempt", attempt).
+			Dur("wait", wait)
+		if err != nil {
+			logEvent = logEvent.Err(err)
+		} else {
+			logEvent = logEvent.Int("status_code", resp.StatusCode)
+		}
+		logEvent.Msg("リクエストをリトライします")
+
+		// 次のリクエストのためにレスポンスボディを破棄
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry はレスポンスまたはエラーがリトライ対象かどうかを判定します
+// 5xx とネットワークエラーはサーバーで処理済みの可能性があるため、冪等なリクエストのみリトライします
+func shouldRetry(ctx context.Context, req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// コンテキストのキャンセル・タイムアウトはリトライしない
+		return isIdempotent(req) && ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return isIdempotent(req) && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// idempotentKey はリクエストが冪等であることをコンテキストに記録するキーです
+type idempotentKey struct{}
+
+// WithIdempotent はリクエストが冪等であることを RetryTransport に伝えるコンテキストを返します
+// 参照系のPOSTなど、再送しても処理が重複しないリクエストをこのコンテキストで送信すると、5xx とネットワークエラーでもリトライします
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent はリクエストを再送しても処理が重複しないかどうかを判定します
+// WithIdempotent で冪等であることを指定したリクエストと、net/http と同様に
+// Idempotency-Key または X-Idempotency-Key ヘッダーを持つリクエストは冪等とみなします
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	if idempotent, _ := req.Context().Value(idempotentKey{}).(bool); idempotent {
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	_, ok := req.Header["X-Idempotency-Key"]
+	return ok
+}
+
+// canRetry はリクエストボディを再送できるかどうかを判定します
+func canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindRequest は2回目以降の送信のためにリクエストボディを巻き戻したリクエストを返します
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("リクエストボディの再作成に失敗しました: %w", err)
+	}
+
+	cloned := req.Clone(req.Context())
+	cloned.Body = body
+	return cloned, nil
+}
+
+// parseRetryAfter はRetry-Afterヘッダー（秒数またはHTTP日付）から待機時間を取得します
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = time.Until(at)
+	} else {
+		return 0, false
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return wait, true
+}
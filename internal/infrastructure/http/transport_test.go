@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRetryTransportRetriesOn429And5xx(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// リトライ時にもリクエストボディが再送されること
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, rate.NewLimiter(rate.Inf, 0), 3)}
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	assert.NoError(t, err)
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryTransportRetriesPostOnlyWhenSafe(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		idempotent bool
+		wantCalls  int32
+	}{
+		{name: "429は処理されていないためリトライする", status: http.StatusTooManyRequests, wantCalls: 2},
+		{name: "5xxは処理済みの可能性があるためリトライしない", status: http.StatusServiceUnavailable, wantCalls: 1},
+		{name: "冪等であることを指定した場合は5xxでもリトライする", status: http.StatusServiceUnavailable, idempotent: true, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: NewRetryTransport(nil, nil, 3)}
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = WithIdempotent(ctx)
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader("payload"))
+			assert.NoError(t, err)
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRetryTransportGivesUpBeforeTimeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	// Retry-After の待機時間が制限時間を超える場合は、待機せずに429のレスポンスを返すこと
+	client := &http.Client{Timeout: 2 * time.Second, Transport: NewRetryTransport(nil, nil, 3)}
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, nil, 2)}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	// 最後のレスポンスがそのまま返されること
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryTransportDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, nil, 3)}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+
+	resp.Header.Set("Retry-After", "2")
+	wait, ok := parseRetryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	// 上限を超える値は切り詰められること
+	resp.Header.Set("Retry-After", "3600")
+	wait, ok = parseRetryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, maxRetryAfter, wait)
+
+	resp.Header.Set("Retry-After", "invalid")
+	_, ok = parseRetryAfter(resp)
+	assert.False(t, ok)
+}
@@ -55,19 +55,27 @@ func (n *SlackNotifier) NotifyCommandResult(result *model.CommandResult) error {
 		return fmt.Errorf("Slack WebhookURLが設定されていません")
 	}
 
-	// 成功/失敗に応じたアイコンとカラーを設定
-	statusEmoji := n.config.SuccessEmoji
+	// 成功/失敗/中断に応じたアイコンとカラーを設定
+	statusEmoji := n.statusEmoji(result)
 	statusColor := "good" // green
-	if !result.IsSuccess() {
-		statusEmoji = n.config.FailureEmoji
+	switch {
+	case result.IsCancelled():
+		statusColor = "warning" // yellow
+	case !result.IsSuccess():
 		statusColor = "danger" // red
 	}
 
 	// メッセージのヘッダーテキスト
 	headerText := fmt.Sprintf("%s %s: %s の処理が終了しました。", statusEmoji, n.config.Username, result.Process)
+	if result.IsCancelled() {
+		headerText = fmt.Sprintf("%s %s: %s の処理を中断しました。", statusEmoji, n.config.Username, result.Process)
+	}
 
 	// 引数部分のテキスト
 	argsText := fmt.Sprintf("```\nProcess: %s\n", result.Process)
+	if result.RunID != 0 {
+		argsText += fmt.Sprintf("Run ID: %d\n", result.RunID)
+	}
 	if len(result.AccountIDs) > 0 {
 		argsText += fmt.Sprintf("AccountIds: %s\n", strings.Join(result.AccountIDs, ", "))
 	}
@@ -80,7 +88,7 @@ func (n *SlackNotifier) NotifyCommandResult(result *model.CommandResult) error {
 	argsText += "```"
 
 	// 結果部分のテキスト
-	resultText := fmt.Sprintf("```\nStatus: %s\nStart: %s\nEnd: %s\nTime: %s\nTotal: %d\nSuccess: %d\nError: %d\nTotal Records: %d\n```",
+	resultText := fmt.Sprintf("```\nStatus: %s\nStart: %s\nEnd: %s\nTime: %s\nTotal: %d\nSuccess: %d\nError: %d\nTotal Records: %d\n",
 		result.Status,
 		model.FormatJST(result.StartTime),
 		model.FormatJST(result.EndTime),
@@ -90,6 +98,26 @@ func (n *SlackNotifier) NotifyCommandResult(result *model.CommandResult) error {
 		result.ErrorCount,
 		result.TotalRecords,
 	)
+	if len(result.FailedAccountIDs) > 0 {
+		resultText += fmt.Sprintf("Failed AccountIds: %s\n", strings.Join(result.FailedAccountIDs, ", "))
+	}
+	if result.HasDiffCounts() {
+		resultText += fmt.Sprintf("Inserted: %d\nUpdated: %d\nUnchanged: %d\n",
+			result.InsertedCount,
+			result.UpdatedCount,
+			result.UnchangedCount,
+		)
+	}
+	if result.RemovedCount > 0 {
+		resultText += fmt.Sprintf("Removed: %d\n", result.RemovedCount)
+	}
+	if result.PageCount > 0 {
+		resultText += fmt.Sprintf("Pages: %d\n", result.PageCount)
+	}
+	if result.ErrorMessage != "" {
+		resultText += fmt.Sprintf("Error Message: %s\n", result.ErrorMessage)
+	}
+	resultText += "```"
 
 	// Slackメッセージの構築
 	message := SlackMessage{
@@ -125,20 +153,20 @@ func (n *SlackNotifier) NotifyCommandResult(result *model.CommandResult) error {
 
 // LogCommandResult はコマンド実行結果をログに出力します
 func (n *SlackNotifier) LogCommandResult(result *model.CommandResult) {
-	// 成功/失敗に応じたアイコンを設定
-	statusEmoji := n.config.SuccessEmoji
-	if !result.IsSuccess() {
-		statusEmoji = n.config.FailureEmoji
-	}
+	// 成功/失敗/中断に応じたアイコンを設定
+	statusEmoji := n.statusEmoji(result)
 
 	// ログメッセージの構築
 	logEvent := log.Info()
-	if !result.IsSuccess() {
+	if result.IsCancelled() {
+		logEvent = log.Warn()
+	} else if !result.IsSuccess() {
 		logEvent = log.Error()
 	}
 
 	// 基本情報をログに追加
 	logEvent.
+		Uint("run_id", result.RunID).
 		Str("process", result.Process).
 		Str("status", result.Status).
 		Str("start_time", model.FormatJST(result.StartTime)).
@@ -159,11 +187,41 @@ func (n *SlackNotifier) LogCommandResult(result *model.CommandResult) {
 	if result.DateTo != "" {
 		logEvent.Str("date_to", result.DateTo)
 	}
+	if len(result.FailedAccountIDs) > 0 {
+		logEvent.Strs("failed_account_ids", result.FailedAccountIDs)
+	}
+	if result.HasDiffCounts() {
+		logEvent.
+			Int("inserted", result.InsertedCount).
+			Int("updated", result.UpdatedCount).
+			Int("unchanged", result.UnchangedCount)
+	}
+	if result.RemovedCount > 0 {
+		logEvent.Int("removed", result.RemovedCount)
+	}
+	if result.PageCount > 0 {
+		logEvent.Int("pages", result.PageCount)
+	}
+	if result.ErrorMessage != "" {
+		logEvent.Str("error_message", result.ErrorMessage)
+	}
 
 	// ログメッセージを出力
 	logEvent.Msgf("%s コマンド実行結果: %s", statusEmoji, result.Process)
 }
 
+// statusEmoji は処理結果のステータスに応じたアイコンを返します
+func (n *SlackNotifier) statusEmoji(result *model.CommandResult) string {
+	switch {
+	case result.IsSuccess():
+		return n.config.SuccessEmoji
+	case result.IsCancelled() && n.config.CancelledEmoji != "":
+		return n.config.CancelledEmoji
+	default:
+		return n.config.FailureEmoji
+	}
+}
+
 // sendWebhook はSlackのWebhookにJSONメッセージを送信します
 func (n *SlackNotifier) sendWebhook(ctx context.Context, jsonMessage []byte) error {
 	// リクエストを作成
@@ -9,27 +9,29 @@ import (
 
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
 )
 
 // AccountRepositoryImpl はMySQLAccountRepositoryインターフェースの実装です
 type AccountRepositoryImpl struct {
-	db *Database
+	db        *Database
+	batchSize int
 }
 
 // NewAccountRepository は新しいAccountRepositoryImplインスタンスを作成します
-func NewAccountRepository(db *gorm.DB) repository.MySQLAccountRepository {
+func NewAccountRepository(db *gorm.DB, cfg *config.Config) repository.MySQLAccountRepository {
 	// データベース接続をラップ
 	database := &Database{
 		DB: db,
 	}
-	return &AccountRepositoryImpl{db: database}
+	return &AccountRepositoryImpl{db: database, batchSize: batchSizeFromConfig(cfg)}
 }
 
 // FindAll は全てのアカウントを取得します
-func (r *AccountRepositoryImpl) FindAll(_ context.Context) ([]entity.Account, error) {
+func (r *AccountRepositoryImpl) FindAll(ctx context.Context) ([]entity.Account, error) {
 	var accounts []entity.Account
 	// リードレプリカを使用
-	result := r.db.GetReader().Find(&accounts)
+	result := r.db.reader(ctx).Find(&accounts)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Msg("アカウント一覧の取得に失敗しました")
 		return nil, result.Error
@@ -37,26 +39,26 @@ func (r *AccountRepositoryImpl) FindAll(_ context.Context) ([]entity.Account, er
 	return accounts, nil
 }
 
-// FindByID は指定されたIDのアカウントを取得します
-func (r *AccountRepositoryImpl) FindByID(_ context.Context, id uint) (*entity.Account, error) {
+// FindByID は指定された取得元とIDのアカウントを取得します
+func (r *AccountRepositoryImpl) FindByID(ctx context.Context, source string, id uint) (*entity.Account, error) {
 	var account entity.Account
 	// リードレプリカを使用
-	result := r.db.GetReader().First(&account, id)
+	result := r.db.reader(ctx).Where("source = ? AND id = ?", source, id).First(&account)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			log.Debug().Uint("id", id).Msg("アカウントが見つかりませんでした")
+			log.Debug().Str("source", source).Uint("id", id).Msg("アカウントが見つかりませんでした")
 			return nil, nil
 		}
-		log.Error().Err(result.Error).Uint("id", id).Msg("アカウントの取得に失敗しました")
+		log.Error().Err(result.Error).Str("source", source).Uint("id", id).Msg("アカウントの取得に失敗しました")
 		return nil, result.Error
 	}
 	return &account, nil
 }
 
 // Create は新しいアカウントを作成します
-func (r *AccountRepositoryImpl) Create(_ context.Context, account *entity.Account) error {
+func (r *AccountRepositoryImpl) Create(ctx context.Context, account *entity.Account) error {
 	// ライターを使用
-	result := r.db.GetWriter().Create(account)
+	result := r.db.writer(ctx).Create(account)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Msg("アカウントの作成に失敗しました")
 		return result.Error
@@ -66,9 +68,9 @@ func (r *AccountRepositoryImpl) Create(_ context.Context, account *entity.Accoun
 }
 
 // Update は既存のアカウントを更新します
-func (r *AccountRepositoryImpl) Update(_ context.Context, account *entity.Account) error {
+func (r *AccountRepositoryImpl) Update(ctx context.Context, account *entity.Account) error {
 	// ライターを使用
-	result := r.db.GetWriter().Save(account)
+	result := r.db.writer(ctx).Save(account)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Uint("id", account.ID).Msg("アカウントの更新に失敗しました")
 		return result.Error
@@ -77,58 +79,67 @@ func (r *AccountRepositoryImpl) Update(_ context.Context, account *entity.Accoun
 	return nil
 }
 
-// Delete は指定されたIDのアカウントを削除します
-func (r *AccountRepositoryImpl) Delete(_ context.Context, id uint) error {
+// Delete は指定された取得元とIDのアカウントを削除します
+func (r *AccountRepositoryImpl) Delete(ctx context.Context, source string, id uint) error {
 	// ライターを使用
-	result := r.db.GetWriter().Delete(&entity.Account{}, id)
+	result := r.db.writer(ctx).Where("source = ? AND id = ?", source, id).Delete(&entity.Account{})
 	if result.Error != nil {
-		log.Error().Err(result.Error).Uint("id", id).Msg("アカウントの削除に失敗しました")
+		log.Error().Err(result.Error).Str("source", source).Uint("id", id).Msg("アカウントの削除に失敗しました")
 		return result.Error
 	}
-	log.Debug().Uint("id", id).Msg("アカウントを削除しました")
+	log.Debug().Str("source", source).Uint("id", id).Msg("アカウントを削除しました")
 	return nil
 }
 
-// SaveAll は複数のアカウントを一括で保存します
-func (r *AccountRepositoryImpl) SaveAll(_ context.Context, accounts []entity.Account) error {
-	// トランザクションを開始
-	tx := r.db.GetWriter().Begin()
-	if tx.Error != nil {
-		log.Error().Err(tx.Error).Msg("トランザクションの開始に失敗しました")
-		return tx.Error
+// accountUpdateColumns はアップサート時に更新するアカウントのカラムです
+// 上流に再び現れたアカウントを復元するため deleted_at も更新します
+var accountUpdateColumns = []string{"name", "status", "api_key", "updated_at", "deleted_at"}
+
+// SaveAll は複数のアカウントを一括で保存します（存在しない場合は作成、存在する場合は更新）
+// batch_size 件ずつアップサートし、全てのチャンクを1つのトランザクションで保存します
+func (r *AccountRepositoryImpl) SaveAll(ctx context.Context, accounts []entity.Account) (repository.UpsertResult, error) {
+	var result repository.UpsertResult
+	err := r.db.writer(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = upsertAll(tx, accounts, accountKey, accountUpdateColumns, r.batchSize)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("アカウントの一括保存に失敗したためロールバックしました")
+		return repository.UpsertResult{}, err
 	}
 
-	// 処理が終了したらロールバックまたはコミット
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			log.Error().Interface("recover", r).Msg("パニックが発生したためロールバックしました")
-		}
-	}()
-
-	// 各アカウントを保存
-	for i := range accounts {
-		if err := tx.Save(&accounts[i]).Error; err != nil {
-			tx.Rollback()
-			log.Error().Err(err).Msg("アカウントの保存に失敗したためロールバックしました")
-			return err
-		}
-	}
+	log.Info().Int("count", len(accounts)).Int("inserted", result.Inserted).Int("updated", result.Updated).Msg("アカウントを一括保存しました")
+	return result, nil
+}
 
-	// コミット
-	if err := tx.Commit().Error; err != nil {
-		log.Error().Err(err).Msg("トランザクションのコミットに失敗しました")
+// RemoveMissingBySource は指定された取得元のアカウントのうち、keepIDs に含まれないもの（上流から削除されたもの）を削除します
+// prune が false の場合は論理削除（deleted_at を設定）、true の場合は物理削除し、削除したレコード数を返します
+func (r *AccountRepositoryImpl) RemoveMissingBySource(ctx context.Context, source string, keepIDs []uint, prune bool) (int, error) {
+	var removed []uint
+	err := r.db.writer(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = removeMissing[entity.Account](tx, func(db *gorm.DB) *gorm.DB {
+			return db.Where("source = ?", source)
+		}, keepIDs, prune, r.batchSize)
 		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Str("source", source).Msg("上流から削除されたアカウントの削除に失敗したためロールバックしました")
+		return 0, err
 	}
 
-	log.Info().Int("count", len(accounts)).Msg("アカウントを一括保存しました")
-	return nil
+	if len(removed) > 0 {
+		log.Info().Str("source", source).Int("removed", len(removed)).Bool("prune", prune).Msg("上流から削除されたアカウントを削除しました")
+	}
+	return len(removed), nil
 }
 
 // Save は単一のアカウントを保存します（存在しない場合は作成、存在する場合は更新）
-func (r *AccountRepositoryImpl) Save(_ context.Context, account entity.Account) error {
+// 論理削除済みのアカウントは復元して更新します
+func (r *AccountRepositoryImpl) Save(ctx context.Context, account entity.Account) error {
 	// ライターを使用
-	result := r.db.GetWriter().Save(&account)
+	result := r.db.writer(ctx).Unscoped().Save(&account)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Interface("account", account).Msg("アカウントの保存に失敗しました")
 		return result.Error
@@ -136,3 +147,8 @@ func (r *AccountRepositoryImpl) Save(_ context.Context, account entity.Account)
 	log.Debug().Uint("id", account.ID).Msg("アカウントを保存しました")
 	return nil
 }
+
+// accountKey はアカウントを識別する取得元とIDの組み合わせを返します
+func accountKey(a entity.Account) sourceKey {
+	return sourceKey{source: a.Source, id: a.ID}
+}
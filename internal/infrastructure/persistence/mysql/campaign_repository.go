@@ -3,28 +3,33 @@ package mysql
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/service"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
 )
 
 // CampaignRepositoryImpl はMySQLCampaignRepositoryインターフェースの実装です
 type CampaignRepositoryImpl struct {
-	db *gorm.DB
+	db        *gorm.DB
+	batchSize int
 }
 
 // NewCampaignRepository は新しいCampaignRepositoryImplインスタンスを作成します
-func NewCampaignRepository(db *gorm.DB) repository.MySQLCampaignRepository {
-	return &CampaignRepositoryImpl{db: db}
+func NewCampaignRepository(db *gorm.DB, cfg *config.Config) repository.MySQLCampaignRepository {
+	return &CampaignRepositoryImpl{db: db, batchSize: batchSizeFromConfig(cfg)}
 }
 
 // FindAll は全てのキャンペーンを取得します
-func (r *CampaignRepositoryImpl) FindAll(_ context.Context) ([]entity.Campaign, error) {
+func (r *CampaignRepositoryImpl) FindAll(ctx context.Context) ([]entity.Campaign, error) {
 	var campaigns []entity.Campaign
-	result := r.db.Find(&campaigns)
+	result := conn(ctx, r.db).Find(&campaigns)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Msg("キャンペーン一覧の取得に失敗しました")
 		return nil, result.Error
@@ -32,35 +37,35 @@ func (r *CampaignRepositoryImpl) FindAll(_ context.Context) ([]entity.Campaign,
 	return campaigns, nil
 }
 
-// FindByID は指定されたIDのキャンペーンを取得します
-func (r *CampaignRepositoryImpl) FindByID(_ context.Context, id uint) (*entity.Campaign, error) {
+// FindByID は指定された取得元とIDのキャンペーンを取得します
+func (r *CampaignRepositoryImpl) FindByID(ctx context.Context, source string, id uint) (*entity.Campaign, error) {
 	var campaign entity.Campaign
-	result := r.db.First(&campaign, id)
+	result := conn(ctx, r.db).Where("source = ? AND id = ?", source, id).First(&campaign)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			log.Debug().Uint("id", id).Msg("キャンペーンが見つかりませんでした")
+			log.Debug().Str("source", source).Uint("id", id).Msg("キャンペーンが見つかりませんでした")
 			return nil, nil
 		}
-		log.Error().Err(result.Error).Uint("id", id).Msg("キャンペーンの取得に失敗しました")
+		log.Error().Err(result.Error).Str("source", source).Uint("id", id).Msg("キャンペーンの取得に失敗しました")
 		return nil, result.Error
 	}
 	return &campaign, nil
 }
 
-// FindByAccountID は指定されたアカウントIDに関連するキャンペーンを全て取得します
-func (r *CampaignRepositoryImpl) FindByAccountID(_ context.Context, accountID uint) ([]entity.Campaign, error) {
+// FindByAccountID は指定された取得元のアカウントに関連するキャンペーンを全て取得します
+func (r *CampaignRepositoryImpl) FindByAccountID(ctx context.Context, source string, accountID uint) ([]entity.Campaign, error) {
 	var campaigns []entity.Campaign
-	result := r.db.Where("account_id = ?", accountID).Find(&campaigns)
+	result := conn(ctx, r.db).Where("source = ? AND account_id = ?", source, accountID).Find(&campaigns)
 	if result.Error != nil {
-		log.Error().Err(result.Error).Uint("account_id", accountID).Msg("アカウントに関連するキャンペーンの取得に失敗しました")
+		log.Error().Err(result.Error).Str("source", source).Uint("account_id", accountID).Msg("アカウントに関連するキャンペーンの取得に失敗しました")
 		return nil, result.Error
 	}
 	return campaigns, nil
 }
 
 // Create は新しいキャンペーンを作成します
-func (r *CampaignRepositoryImpl) Create(_ context.Context, campaign *entity.Campaign) error {
-	result := r.db.Create(campaign)
+func (r *CampaignRepositoryImpl) Create(ctx context.Context, campaign *entity.Campaign) error {
+	result := conn(ctx, r.db).Create(campaign)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Msg("キャンペーンの作成に失敗しました")
 		return result.Error
@@ -69,8 +74,8 @@ func (r *CampaignRepositoryImpl) Create(_ context.Context, campaign *entity.Camp
 }
 
 // Update は既存のキャンペーンを更新します
-func (r *CampaignRepositoryImpl) Update(_ context.Context, campaign *entity.Campaign) error {
-	result := r.db.Save(campaign)
+func (r *CampaignRepositoryImpl) Update(ctx context.Context, campaign *entity.Campaign) error {
+	result := conn(ctx, r.db).Save(campaign)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Uint("id", campaign.ID).Msg("キャンペーンの更新に失敗しました")
 		return result.Error
@@ -78,50 +83,187 @@ func (r *CampaignRepositoryImpl) Update(_ context.Context, campaign *entity.Camp
 	return nil
 }
 
-// Delete は指定されたIDのキャンペーンを削除します
-func (r *CampaignRepositoryImpl) Delete(_ context.Context, id uint) error {
-	result := r.db.Delete(&entity.Campaign{}, id)
+// Delete は指定された取得元とIDのキャンペーンを削除します
+func (r *CampaignRepositoryImpl) Delete(ctx context.Context, source string, id uint) error {
+	result := conn(ctx, r.db).Where("source = ? AND id = ?", source, id).Delete(&entity.Campaign{})
 	if result.Error != nil {
-		log.Error().Err(result.Error).Uint("id", id).Msg("キャンペーンの削除に失敗しました")
+		log.Error().Err(result.Error).Str("source", source).Uint("id", id).Msg("キャンペーンの削除に失敗しました")
 		return result.Error
 	}
 	return nil
 }
 
-// SaveAll は複数のキャンペーンを一括で保存します
-func (r *CampaignRepositoryImpl) SaveAll(_ context.Context, campaigns []entity.Campaign) error {
-	// トランザクションを開始
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		// 既存のデータを取得
-		var existingCampaigns []entity.Campaign
-		if err := tx.Find(&existingCampaigns).Error; err != nil {
-			log.Error().Err(err).Msg("既存キャンペーンの取得に失敗しました")
+// campaignUpdateColumns はアップサート時に更新するキャンペーンのカラムです
+// 上流に再び現れたキャンペーンを復元するため deleted_at も更新します
+var campaignUpdateColumns = []string{"account_id", "name", "status", "budget", "start_date", "end_date", "updated_at", "deleted_at"}
+
+// SaveAll は複数のキャンペーンを一括で保存します（存在しない場合は作成、存在する場合は更新）
+// batch_size 件ずつアップサートし、全てのチャンクを1つのトランザクションで保存します
+// 予算・ステータス・期間が変わったキャンペーンは、同じトランザクションで変更履歴に新しい版を記録します
+func (r *CampaignRepositoryImpl) SaveAll(ctx context.Context, campaigns []entity.Campaign) (repository.UpsertResult, error) {
+	var result repository.UpsertResult
+	versions := 0
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = upsertAll(tx, campaigns, campaignKey, campaignUpdateColumns, r.batchSize)
+		if err != nil {
 			return err
 		}
+		versions, err = recordCampaignHistory(tx, campaigns, time.Now(), r.batchSize)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("キャンペーンの一括保存に失敗したためロールバックしました")
+		return repository.UpsertResult{}, err
+	}
 
-		// 既存のIDをマップに格納
-		existingMap := make(map[uint]entity.Campaign)
-		for _, camp := range existingCampaigns {
-			existingMap[camp.ID] = camp
+	log.Info().Int("count", len(campaigns)).Int("inserted", result.Inserted).Int("updated", result.Updated).Int("history_versions", versions).Msg("キャンペーンを一括保存しました")
+	return result, nil
+}
+
+// RemoveMissingByAccountID は指定された取得元のアカウントのキャンペーンのうち、keepIDs に含まれないもの（上流から削除されたもの）を削除します
+// prune が false の場合は論理削除（deleted_at を設定）、true の場合は物理削除し、削除したレコード数を返します
+func (r *CampaignRepositoryImpl) RemoveMissingByAccountID(ctx context.Context, source string, accountID uint, keepIDs []uint, prune bool) (int, error) {
+	var removed []uint
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = removeMissing[entity.Campaign](tx, func(db *gorm.DB) *gorm.DB {
+			return db.Where("source = ? AND account_id = ?", source, accountID)
+		}, keepIDs, prune, r.batchSize)
+		if err != nil {
+			return err
 		}
+		// 削除したキャンペーンの現在の版を終了する（物理削除の場合も履歴は残す）
+		return closeCampaignHistory(tx, source, removed, time.Now(), r.batchSize)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("source", source).Uint("account_id", accountID).Msg("上流から削除されたキャンペーンの削除に失敗したためロールバックしました")
+		return 0, err
+	}
+
+	if len(removed) > 0 {
+		log.Info().Str("source", source).Uint("account_id", accountID).Int("removed", len(removed)).Bool("prune", prune).Msg("上流から削除されたキャンペーンを削除しました")
+	}
+	return len(removed), nil
+}
+
+// FindHistoryByCampaignID は指定された取得元のキャンペーンの変更履歴を版の古い順に取得します
+func (r *CampaignRepositoryImpl) FindHistoryByCampaignID(ctx context.Context, source string, campaignID uint) ([]entity.CampaignHistory, error) {
+	var histories []entity.CampaignHistory
+	result := conn(ctx, r.db).Where("source = ? AND campaign_id = ?", source, campaignID).Order("version").Find(&histories)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("source", source).Uint("campaign_id", campaignID).Msg("キャンペーンの変更履歴の取得に失敗しました")
+		return nil, result.Error
+	}
+	return histories, nil
+}
+
+// FindWatermarks は指定された取得元の差分同期の基準日時をアカウントIDごとに取得します
+func (r *CampaignRepositoryImpl) FindWatermarks(ctx context.Context, source string) (map[uint]time.Time, error) {
+	var marks []entity.CampaignSyncWatermark
+	result := conn(ctx, r.db).Where("source = ?", source).Find(&marks)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("source", source).Msg("差分同期の基準日時の取得に失敗しました")
+		return nil, result.Error
+	}
+
+	watermarks := make(map[uint]time.Time, len(marks))
+	for _, mark := range marks {
+		watermarks[mark.AccountID] = mark.HighWaterMark
+	}
+	return watermarks, nil
+}
 
-		// 新しいキャンペーンを作成または更新
-		for _, campaign := range campaigns {
-			if _, exists := existingMap[campaign.ID]; exists {
-				// 既存のキャンペーンを更新
-				if err := tx.Save(&campaign).Error; err != nil {
-					log.Error().Err(err).Uint("id", campaign.ID).Msg("キャンペーンの更新に失敗しました")
-					return err
+// SaveWatermark は指定されたアカウントの差分同期の基準日時を保存します（存在しない場合は作成、存在する場合は更新）
+func (r *CampaignRepositoryImpl) SaveWatermark(ctx context.Context, accountID uint, source string, highWaterMark time.Time) error {
+	mark := entity.CampaignSyncWatermark{AccountID: accountID, Source: source, HighWaterMark: highWaterMark}
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"high_water_mark", "updated_at"}),
+	}).Create(&mark)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Uint("account_id", accountID).Str("source", source).Msg("差分同期の基準日時の保存に失敗しました")
+		return result.Error
+	}
+	log.Debug().Uint("account_id", accountID).Str("source", source).Time("high_water_mark", highWaterMark).Msg("差分同期の基準日時を保存しました")
+	return nil
+}
+
+// DeleteWatermark は指定されたアカウントの差分同期の基準日時を削除します（次回は全件を取得します）
+func (r *CampaignRepositoryImpl) DeleteWatermark(ctx context.Context, accountID uint, source string) error {
+	result := conn(ctx, r.db).Where("account_id = ? AND source = ?", accountID, source).Delete(&entity.CampaignSyncWatermark{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Uint("account_id", accountID).Str("source", source).Msg("差分同期の基準日時の削除に失敗しました")
+		return result.Error
+	}
+	return nil
+}
+
+// campaignKey はキャンペーンを識別する取得元とIDの組み合わせを返します
+func campaignKey(c entity.Campaign) sourceKey {
+	return sourceKey{source: c.Source, id: c.ID}
+}
+
+// recordCampaignHistory は保存したキャンペーンを最新の版と取得元ごとに batchSize 件ずつ比較し、変更があれば現在の版を終了して新しい版を記録します
+// 記録した新しい版の件数を返します
+func recordCampaignHistory(tx *gorm.DB, campaigns []entity.Campaign, now time.Time, batchSize int) (int, error) {
+	campaigns = dedupeByKey(campaigns, campaignKey)
+
+	versions := 0
+	for _, group := range groupBySource(campaigns, campaignKey) {
+		source := group[0].Source
+		for start := 0; start < len(group); start += batchSize {
+			end := min(start+batchSize, len(group))
+			chunk := group[start:end]
+
+			ids := make([]uint, len(chunk))
+			for i, campaign := range chunk {
+				ids[i] = campaign.ID
+			}
+
+			// キャンペーンごとの最新の版（終了済みの版を含む）を取得
+			latestVersions := tx.Model(&entity.CampaignHistory{}).
+				Select("campaign_id, MAX(version)").
+				Where("source = ? AND campaign_id IN ?", source, ids).
+				Group("campaign_id")
+			var latest []entity.CampaignHistory
+			if err := tx.Where("source = ? AND (campaign_id, version) IN (?)", source, latestVersions).Find(&latest).Error; err != nil {
+				return versions, err
+			}
+
+			changes := service.TrackCampaignHistory(latest, chunk, now)
+
+			if len(changes.Closed) > 0 {
+				closedIDs := make([]uint, len(changes.Closed))
+				for i, history := range changes.Closed {
+					closedIDs[i] = history.ID
 				}
-			} else {
-				// 新しいキャンペーンを作成
-				if err := tx.Create(&campaign).Error; err != nil {
-					log.Error().Err(err).Msg("キャンペーンの作成に失敗しました")
-					return err
+				if err := tx.Model(&entity.CampaignHistory{}).Where("id IN ?", closedIDs).Update("valid_to", now).Error; err != nil {
+					return versions, err
+				}
+			}
+			if len(changes.Opened) > 0 {
+				if err := tx.Create(&changes.Opened).Error; err != nil {
+					return versions, err
 				}
 			}
+
+			versions += len(changes.Opened)
 		}
+	}
 
-		return nil
-	})
+	return versions, nil
+}
+
+// closeCampaignHistory は指定された取得元のキャンペーンの現在の版を batchSize 件ずつ終了します
+func closeCampaignHistory(tx *gorm.DB, source string, campaignIDs []uint, now time.Time, batchSize int) error {
+	for start := 0; start < len(campaignIDs); start += batchSize {
+		end := min(start+batchSize, len(campaignIDs))
+		if err := tx.Model(&entity.CampaignHistory{}).
+			Where("source = ? AND campaign_id IN ? AND valid_to IS NULL", source, campaignIDs[start:end]).
+			Update("valid_to", now).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func TestCampaignSaveAllHistory(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewCampaignRepository(db, nil)
+	ctx := context.Background()
+
+	// 初回の保存で版1を記録
+	_, err := repo.SaveAll(ctx, []entity.Campaign{
+		{Source: entity.SourceAPI1, ID: 1, AccountID: 1, Name: "A", Status: "active", Budget: 1000},
+		{Source: entity.SourceAPI1, ID: 2, AccountID: 1, Name: "B", Status: "active", Budget: 2000},
+	})
+	assert.NoError(t, err)
+
+	// 予算が変わったキャンペーンのみ新しい版を記録（名前のみの変更は記録しない）
+	_, err = repo.SaveAll(ctx, []entity.Campaign{
+		{Source: entity.SourceAPI1, ID: 1, AccountID: 1, Name: "A", Status: "active", Budget: 1500},
+		{Source: entity.SourceAPI1, ID: 2, AccountID: 1, Name: "B2", Status: "active", Budget: 2000},
+	})
+	assert.NoError(t, err)
+
+	histories, err := repo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, 1000.0, histories[0].Budget)
+	assert.False(t, histories[0].IsCurrent())
+	assert.Equal(t, 1500.0, histories[1].Budget)
+	assert.Equal(t, 2, histories[1].Version)
+	assert.True(t, histories[1].IsCurrent())
+	assert.True(t, histories[0].ValidTo.Equal(histories[1].ValidFrom))
+
+	histories, err = repo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 1)
+
+	// 上流から削除されたキャンペーンは現在の版を終了し、再び現れた場合は新しい版を記録
+	_, err = repo.RemoveMissingByAccountID(ctx, entity.SourceAPI1, 1, []uint{1}, false)
+	assert.NoError(t, err)
+	histories, err = repo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 2)
+	assert.NoError(t, err)
+	assert.False(t, histories[0].IsCurrent())
+
+	_, err = repo.SaveAll(ctx, []entity.Campaign{{Source: entity.SourceAPI1, ID: 2, AccountID: 1, Name: "B2", Status: "active", Budget: 2000}})
+	assert.NoError(t, err)
+	histories, err = repo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 2)
+	assert.True(t, histories[1].IsCurrent())
+}
+
+func TestCampaignSourceQualifiedKey(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewCampaignRepository(db, nil)
+	ctx := context.Background()
+
+	// 取得元が異なれば同じIDでも別のキャンペーンとして保存する
+	result, err := repo.SaveAll(ctx, []entity.Campaign{
+		{Source: entity.SourceAPI1, ID: 1, AccountID: 1, Name: "api1", Budget: 1000},
+		{Source: entity.SourceAPI2, ID: 1, AccountID: 1, Name: "api2", Budget: 2000},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Inserted)
+
+	result, err = repo.SaveAll(ctx, []entity.Campaign{{Source: entity.SourceAPI2, ID: 1, AccountID: 1, Name: "api2", Budget: 3000}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+
+	campaign, err := repo.FindByID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "api1", campaign.Name)
+	assert.Equal(t, 1000.0, campaign.Budget)
+
+	// 変更履歴も取得元ごとに記録する
+	histories, err := repo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 1)
+	histories, err = repo.FindHistoryByCampaignID(ctx, entity.SourceAPI2, 1)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 2)
+
+	// 削除の検出は指定された取得元のキャンペーンのみを対象にする
+	removed, err := repo.RemoveMissingByAccountID(ctx, entity.SourceAPI2, 1, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	campaign, err = repo.FindByID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, campaign)
+	histories, err = repo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.True(t, histories[0].IsCurrent())
+}
+
+func TestCampaignWatermarks(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewCampaignRepository(db, nil)
+	ctx := context.Background()
+
+	mark := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.SaveWatermark(ctx, 1, entity.SourceAPI1, mark))
+	assert.NoError(t, repo.SaveWatermark(ctx, 2, entity.SourceAPI1, mark))
+	assert.NoError(t, repo.SaveWatermark(ctx, 1, entity.SourceAPI2, mark))
+
+	// 同じアカウント・取得元の基準日時は上書きする
+	assert.NoError(t, repo.SaveWatermark(ctx, 1, entity.SourceAPI1, mark.Add(time.Hour)))
+
+	watermarks, err := repo.FindWatermarks(ctx, entity.SourceAPI1)
+	assert.NoError(t, err)
+	assert.Len(t, watermarks, 2)
+	assert.True(t, mark.Add(time.Hour).Equal(watermarks[1]))
+	assert.True(t, mark.Equal(watermarks[2]))
+
+	assert.NoError(t, repo.DeleteWatermark(ctx, 1, entity.SourceAPI1))
+	watermarks, err = repo.FindWatermarks(ctx, entity.SourceAPI1)
+	assert.NoError(t, err)
+	assert.Len(t, watermarks, 1)
+
+	// 他の取得元の基準日時は削除しない
+	watermarks, err = repo.FindWatermarks(ctx, entity.SourceAPI2)
+	assert.NoError(t, err)
+	assert.Len(t, watermarks, 1)
+}
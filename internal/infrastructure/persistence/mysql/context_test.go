@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// newCampaigns は1件ずつ異なるIDのキャンペーンを n 件作成します
+func newCampaigns(n int) []entity.Campaign {
+	campaigns := make([]entity.Campaign, n)
+	for i := range campaigns {
+		campaigns[i] = entity.Campaign{ID: uint(i + 1), AccountID: 1, Name: fmt.Sprintf("キャンペーン%d", i+1)}
+	}
+	return campaigns
+}
+
+func TestSaveAllCancelledContext(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	campaignRepo := NewCampaignRepository(db, nil)
+	accountRepo := NewAccountRepository(db, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// キャンセル済みのコンテキストでは読み書きを行わない
+	_, err := campaignRepo.SaveAll(ctx, newCampaigns(3))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = accountRepo.SaveAll(ctx, []entity.Account{{Source: entity.SourceAPI1, ID: 1, Name: "A"}})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = campaignRepo.FindAll(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = accountRepo.FindByID(ctx, entity.SourceAPI1, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var count int64
+	assert.NoError(t, db.Model(&entity.Campaign{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestSaveAllCancelledDuringBatches(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	cfg := &config.Config{}
+	cfg.Database.BatchSize = 1
+	repo := NewCampaignRepository(db, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 2チャンク目を保存した時点でキャンセルする
+	chunks := 0
+	assert.NoError(t, db.Callback().Create().After("gorm:create").Register("test:cancel", func(tx *gorm.DB) {
+		if tx.Statement.Table != "campaigns" {
+			return
+		}
+		chunks++
+		if chunks == 2 {
+			cancel()
+		}
+	}))
+
+	_, err := repo.SaveAll(ctx, newCampaigns(10))
+	assert.ErrorIs(t, err, context.Canceled)
+	// 残りのチャンクは実行しない
+	assert.Equal(t, 2, chunks)
+
+	// 保存済みのチャンクもロールバックされる
+	var count int64
+	assert.NoError(t, db.Model(&entity.Campaign{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
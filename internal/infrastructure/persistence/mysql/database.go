@@ -119,16 +119,21 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 		db.DB = gormDB
 	}
 
-	// 自動マイグレーションが有効な場合は実行
-	if cfg.Database.AutoMigrate {
-		if err := autoMigrate(db.DB); err != nil {
-			return nil, fmt.Errorf("マイグレーションに失敗しました: %w", err)
-		}
-	}
-
 	return db, nil
 }
 
+// AutoMigrate は自動マイグレーションが有効な場合に、未適用のマイグレーションを全て適用します
+// 実行するコマンドが確定してから呼び出せるよう、接続時（NewDatabase）には適用しません
+func (db *Database) AutoMigrate(ctx context.Context) error {
+	if !db.Config.Database.AutoMigrate {
+		return nil
+	}
+	if err := autoMigrate(ctx, db.DB); err != nil {
+		return fmt.Errorf("マイグレーションに失敗しました: %w", err)
+	}
+	return nil
+}
+
 // setupAuroraConnection はAuroraクラスター接続を設定します
 func (db *Database) setupAuroraConnection(ctx context.Context, gormConfig *gorm.Config) (*gorm.DB, error) {
 	cfg := db.Config
@@ -264,9 +269,20 @@ func (db *Database) GetWriter() *gorm.DB {
 	return db.DB.Clauses(dbresolver.Write)
 }
 
-// autoMigrate はデータベースのマイグレーションを実行します
-func autoMigrate(_ *gorm.DB) error {
-	// ここにマイグレーション対象のモデルを追加
-	// 例: return db.AutoMigrate(&models.User{}, &models.Task{})
+// autoMigrate は未適用のバージョン管理されたマイグレーションを全て適用します
+// 本番環境では auto_migrate を無効にし、migrate コマンドで適用してください
+func autoMigrate(ctx context.Context, db *gorm.DB) error {
+	migrator, err := NewSchemaMigrationRepository(db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		log.Info().Int("applied", len(applied)).Msg("自動マイグレーションを実行しました")
+	}
 	return nil
 }
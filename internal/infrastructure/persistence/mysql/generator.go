@@ -1,11 +1,11 @@
 package mysql
 
 import (
+	"context"
+
 	"github.com/rs/zerolog/log"
 	"gorm.io/gen"
 	"gorm.io/gorm"
-
-	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 )
 
 // GenerateModels はGORM genを使用してモデルを生成します
@@ -45,8 +45,8 @@ func GenerateModels(db *gorm.DB) error {
 
 // InitDatabase はデータベースの初期化とマイグレーションを行います
 func InitDatabase(db *gorm.DB) error {
-	// テーブルの作成
-	if err := db.AutoMigrate(&entity.Account{}, &entity.Campaign{}); err != nil {
+	// テーブルの作成（バージョン管理されたマイグレーションを適用）
+	if err := autoMigrate(context.Background(), db); err != nil {
 		return err
 	}
 
@@ -0,0 +1,308 @@
+package mysql
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// migrationFiles はダイアレクトごとのマイグレーションSQLです
+// ファイル名は <バージョン>_<名前>.up.sql / <バージョン>_<名前>.down.sql の形式です
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// schemaMigrationsTable は適用済みのマイグレーションを記録するテーブルです
+const schemaMigrationsTable = "schema_migrations"
+
+// migrationFilePattern はマイグレーションファイル名の形式です
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// migration はバージョンごとのマイグレーションSQLです
+type migration struct {
+	version uint
+	name    string
+	up      string
+	down    string
+}
+
+// schemaMigrationRecord は schema_migrations テーブルのレコードです
+type schemaMigrationRecord struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName はテーブル名を返します
+func (schemaMigrationRecord) TableName() string {
+	return schemaMigrationsTable
+}
+
+// SchemaMigrationRepositoryImpl はSchemaMigrationRepositoryインターフェースの実装です
+type SchemaMigrationRepositoryImpl struct {
+	db         *Database
+	migrations []migration
+}
+
+// NewSchemaMigrationRepository は接続先のダイアレクトのマイグレーションを読み込み、SchemaMigrationRepositoryを作成します
+func NewSchemaMigrationRepository(db *gorm.DB) (repository.SchemaMigrationRepository, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	// データベース接続をラップ
+	database := &Database{
+		DB: db,
+	}
+	return &SchemaMigrationRepositoryImpl{db: database, migrations: migrations}, nil
+}
+
+// Up は未適用のマイグレーションを古い順に適用します
+// マイグレーションごとにトランザクション内でSQLを実行し、schema_migrations に記録します
+// ただしMySQLではDDLが暗黙的にコミットされるため、ロールバックできるのはDMLと schema_migrations への記録のみです
+// 複数の文を含むマイグレーションが途中で失敗した場合は、失敗した文より前のDDLが適用されたまま残ります（復旧手順はREADMEを参照）
+func (r *SchemaMigrationRepositoryImpl) Up(ctx context.Context, steps int) ([]repository.SchemaMigration, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []repository.SchemaMigration
+	for _, m := range r.migrations {
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+
+		now := time.Now()
+		err := r.db.GetWriter().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, m.up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigrationRecord{Version: m.version, Name: m.name, AppliedAt: now}).Error
+		})
+		if err != nil {
+			log.Error().Err(err).Uint("version", m.version).Str("name", m.name).Msg("マイグレーションの適用に失敗しました")
+			return done, fmt.Errorf("マイグレーション %04d_%s の適用に失敗しました%s: %w", m.version, m.name, r.partialHint(m.up), err)
+		}
+
+		log.Info().Uint("version", m.version).Str("name", m.name).Msg("マイグレーションを適用しました")
+		done = append(done, repository.SchemaMigration{Version: m.version, Name: m.name, AppliedAt: &now})
+	}
+
+	return done, nil
+}
+
+// Down は適用済みのマイグレーションを新しい順に steps 件取り消します
+func (r *SchemaMigrationRepositoryImpl) Down(ctx context.Context, steps int) ([]repository.SchemaMigration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("取り消すマイグレーションの件数は1以上を指定してください: %d", steps)
+	}
+
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 適用済みのバージョンを新しい順に並べる
+	versions := make([]uint, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	var done []repository.SchemaMigration
+	for _, version := range versions {
+		if len(done) >= steps {
+			break
+		}
+
+		m, ok := r.find(version)
+		if !ok {
+			return done, fmt.Errorf("適用済みのマイグレーション %04d のSQLファイルが見つかりません", version)
+		}
+
+		err := r.db.GetWriter().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, m.down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigrationRecord{}, m.version).Error
+		})
+		if err != nil {
+			log.Error().Err(err).Uint("version", m.version).Str("name", m.name).Msg("マイグレーションの取り消しに失敗しました")
+			return done, fmt.Errorf("マイグレーション %04d_%s の取り消しに失敗しました%s: %w", m.version, m.name, r.partialHint(m.down), err)
+		}
+
+		log.Info().Uint("version", m.version).Str("name", m.name).Msg("マイグレーションを取り消しました")
+		done = append(done, repository.SchemaMigration{Version: m.version, Name: m.name})
+	}
+
+	return done, nil
+}
+
+// partialHint は失敗したマイグレーションが途中まで適用されている可能性がある場合に、エラーに添える説明を返します
+// MySQLではDDLが暗黙的にコミットされるため、複数の文を含むマイグレーションはロールバックされずに途中の状態で残ります
+func (r *SchemaMigrationRepositoryImpl) partialHint(sql string) string {
+	if r.db.DB.Dialector.Name() != "mysql" || len(splitStatements(sql)) < 2 {
+		return ""
+	}
+	return "（MySQLではDDLが暗黙的にコミットされるため、失敗した文より前のDDLは適用されたままです。スキーマを確認して手動で復旧してください）"
+}
+
+// Status は全てのマイグレーションと適用状況をバージョン順に返します
+func (r *SchemaMigrationRepositoryImpl) Status(ctx context.Context) ([]repository.SchemaMigration, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]repository.SchemaMigration, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		status := repository.SchemaMigration{Version: m.version, Name: m.name}
+		if record, ok := applied[m.version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// appliedVersions は schema_migrations テーブルを作成し、適用済みのマイグレーションを返します
+func (r *SchemaMigrationRepositoryImpl) appliedVersions(ctx context.Context) (map[uint]schemaMigrationRecord, error) {
+	db := r.db.GetWriter().WithContext(ctx)
+
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + schemaMigrationsTable + ` (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`).Error; err != nil {
+		return nil, fmt.Errorf("%s テーブルの作成に失敗しました: %w", schemaMigrationsTable, err)
+	}
+
+	var records []schemaMigrationRecord
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("適用済みのマイグレーションの取得に失敗しました: %w", err)
+	}
+
+	applied := make(map[uint]schemaMigrationRecord, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// find は指定されたバージョンのマイグレーションを返します
+func (r *SchemaMigrationRepositoryImpl) find(version uint) (migration, bool) {
+	for _, m := range r.migrations {
+		if m.version == version {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
+
+// loadMigrations は埋め込まれたSQLファイルからダイアレクトのマイグレーションを読み込み、バージョン順に返します
+func loadMigrations(dialect string) ([]migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("未対応のデータベースダイアレクトのマイグレーションです: %s", dialect)
+	}
+
+	byVersion := map[uint]*migration{}
+	for _, entry := range entries {
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("マイグレーションファイル名の形式が不正です: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("マイグレーションのバージョンが不正です: %s", entry.Name())
+		}
+
+		data, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("マイグレーションファイルの読み込みに失敗しました: %w", err)
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &migration{version: uint(version), name: matches[2]}
+			byVersion[uint(version)] = m
+		}
+		if m.name != matches[2] {
+			return nil, fmt.Errorf("マイグレーションのバージョンが重複しています: %s", entry.Name())
+		}
+
+		if matches[3] == "up" {
+			m.up = string(data)
+		} else {
+			m.down = string(data)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("マイグレーション %04d_%s には up と down の両方のSQLが必要です", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	return migrations, nil
+}
+
+// execStatements はSQLを文ごとに実行します
+// 複数の文を1回で実行できないドライバーがあるため、行末の ; で文を区切ります
+func execStatements(tx *gorm.DB, sql string) error {
+	for _, statement := range splitStatements(sql) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements はSQLを行末の ; で文に分割します（-- で始まるコメント行は除きます）
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
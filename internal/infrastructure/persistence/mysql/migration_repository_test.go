@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+
+	// インメモリデータベースは接続ごとに別のデータベースになるため、接続を1つに制限する
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestSchemaMigrationUpDown(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo, err := NewSchemaMigrationRepository(db)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	migrations, err := loadMigrations("sqlite")
+	assert.NoError(t, err)
+	total := len(migrations)
+
+	// 全て適用
+	applied, err := repo.Up(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, applied, total)
+	assert.True(t, db.Migrator().HasTable("accounts"))
+	assert.True(t, db.Migrator().HasTable("campaigns"))
+	assert.NoError(t, db.Create(&entity.Account{ID: 1, Source: entity.SourceAPI1, Name: "テスト"}).Error)
+
+	// 適用済みのものは再適用しない
+	applied, err = repo.Up(ctx, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// 新しい順に、最初のマイグレーション以外を取り消す
+	reverted, err := repo.Down(ctx, total-1)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, total-1)
+	assert.Equal(t, uint(total), reverted[0].Version)
+	assert.Equal(t, uint(2), reverted[total-2].Version)
+	assert.False(t, db.Migrator().HasTable("campaigns"))
+	assert.False(t, db.Migrator().HasColumn("accounts", "deleted_at"))
+
+	statuses, err := repo.Status(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, total)
+	assert.True(t, statuses[0].Applied())
+	for _, status := range statuses[1:] {
+		assert.False(t, status.Applied())
+	}
+
+	// 取り消しの件数は1以上
+	_, err = repo.Down(ctx, 0)
+	assert.Error(t, err)
+}
+
+func TestSchemaMigrationKeyBySource(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo, err := NewSchemaMigrationRepository(db)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// 取得元を (source, id) の主キーにする前のデータ
+	_, err = repo.Up(ctx, 7)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Exec("INSERT INTO accounts (id, source, name) VALUES (1, '', '取得元なし')").Error)
+	assert.NoError(t, db.Exec("INSERT INTO campaigns (id, account_id, source, name) VALUES (1, 1, '', '取得元なし')").Error)
+
+	// 取得元が記録されていない行は api1 として扱う
+	_, err = repo.Up(ctx, 0)
+	assert.NoError(t, err)
+	var account entity.Account
+	assert.NoError(t, db.Where("source = ? AND id = ?", entity.SourceAPI1, 1).First(&account).Error)
+	assert.Equal(t, "取得元なし", account.Name)
+	var campaign entity.Campaign
+	assert.NoError(t, db.Where("source = ? AND id = ?", entity.SourceAPI1, 1).First(&campaign).Error)
+
+	// 取得元が異なれば同じIDの行を保存できる
+	assert.NoError(t, db.Create(&entity.Account{ID: 1, Source: entity.SourceAPI2, Name: "顧客"}).Error)
+	assert.NoError(t, db.Create(&entity.Campaign{ID: 1, Source: entity.SourceAPI2, Name: "キャンペーン"}).Error)
+	assert.Error(t, db.Create(&entity.Account{ID: 1, Source: entity.SourceAPI2, Name: "重複"}).Error)
+}
+
+func TestLoadMigrationsDialects(t *testing.T) {
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		migrations, err := loadMigrations(dialect)
+		assert.NoError(t, err, dialect)
+		assert.NotEmpty(t, migrations, dialect)
+	}
+
+	_, err := loadMigrations("oracle")
+	assert.Error(t, err)
+}
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements(`-- コメント
+CREATE TABLE a (
+    id INTEGER
+);
+CREATE INDEX idx_a ON a (id);
+`)
+	assert.Equal(t, []string{"CREATE TABLE a (\n    id INTEGER\n);", "CREATE INDEX idx_a ON a (id);"}, statements)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    account_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT '',
+    budget DOUBLE NOT NULL DEFAULT 0,
+    start_date DATETIME(3) NULL,
+    end_date DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_campaigns_account_id (account_id),
+    INDEX idx_campaigns_source (source)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
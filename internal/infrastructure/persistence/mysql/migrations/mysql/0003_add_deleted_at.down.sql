@@ -0,0 +1,4 @@
+DROP INDEX idx_campaigns_deleted_at ON campaigns;
+ALTER TABLE campaigns DROP COLUMN deleted_at;
+DROP INDEX idx_accounts_deleted_at ON accounts;
+ALTER TABLE accounts DROP COLUMN deleted_at;
@@ -0,0 +1,18 @@
+-- キャンペーンの変更履歴（SCD Type 2）
+CREATE TABLE IF NOT EXISTS campaign_histories (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    campaign_id BIGINT UNSIGNED NOT NULL,
+    version INT NOT NULL,
+    account_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT '',
+    budget DOUBLE NOT NULL DEFAULT 0,
+    start_date DATETIME(3) NULL,
+    end_date DATETIME(3) NULL,
+    valid_from DATETIME(3) NOT NULL,
+    valid_to DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_campaign_histories_campaign_version (campaign_id, version)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
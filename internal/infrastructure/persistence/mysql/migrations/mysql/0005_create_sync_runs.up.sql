@@ -0,0 +1,27 @@
+-- コマンドの実行履歴
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    process VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT '',
+    account_ids TEXT NULL,
+    date_from VARCHAR(32) NOT NULL DEFAULT '',
+    date_to VARCHAR(32) NOT NULL DEFAULT '',
+    started_at DATETIME(3) NOT NULL,
+    finished_at DATETIME(3) NULL,
+    total_count INT NOT NULL DEFAULT 0,
+    success_count INT NOT NULL DEFAULT 0,
+    error_count INT NOT NULL DEFAULT 0,
+    total_records INT NOT NULL DEFAULT 0,
+    inserted_count INT NOT NULL DEFAULT 0,
+    updated_count INT NOT NULL DEFAULT 0,
+    unchanged_count INT NOT NULL DEFAULT 0,
+    removed_count INT NOT NULL DEFAULT 0,
+    page_count INT NOT NULL DEFAULT 0,
+    failed_account_ids TEXT NULL,
+    error_message TEXT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_sync_runs_process (process),
+    INDEX idx_sync_runs_status (status),
+    INDEX idx_sync_runs_started_at (started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,9 @@
+-- 実行ごとにキャンペーンの同期が完了したアカウント（--resume で使用）
+CREATE TABLE IF NOT EXISTS sync_run_checkpoints (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    run_id BIGINT UNSIGNED NOT NULL,
+    account_id BIGINT UNSIGNED NOT NULL,
+    completed_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_sync_run_checkpoints_run_account (run_id, account_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
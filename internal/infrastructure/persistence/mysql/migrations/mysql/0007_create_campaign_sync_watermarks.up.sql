@@ -0,0 +1,10 @@
+-- アカウントごとのキャンペーンの差分同期の基準日時
+CREATE TABLE IF NOT EXISTS campaign_sync_watermarks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    account_id BIGINT UNSIGNED NOT NULL,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    high_water_mark DATETIME(3) NOT NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_campaign_sync_watermarks_account_source (account_id, source)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
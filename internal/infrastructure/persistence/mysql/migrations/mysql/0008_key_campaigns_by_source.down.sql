@@ -0,0 +1,3 @@
+-- 取得元が異なる同じIDのキャンペーンが保存されている場合は取り消せない
+ALTER TABLE campaign_histories DROP INDEX idx_campaign_histories_source_campaign_version, ADD UNIQUE INDEX idx_campaign_histories_campaign_version (campaign_id, version);
+ALTER TABLE campaigns DROP PRIMARY KEY, ADD PRIMARY KEY (id), MODIFY id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;
@@ -0,0 +1,6 @@
+-- 取得元ごとにキャンペーンIDの空間が異なるため、キャンペーンと変更履歴を (source, id) で識別する
+-- 取得元が記録されていないキャンペーンは api1 として扱う
+UPDATE campaigns SET source = 'api1' WHERE source = '';
+UPDATE campaign_histories SET source = 'api1' WHERE source = '';
+ALTER TABLE campaigns MODIFY id BIGINT UNSIGNED NOT NULL, DROP PRIMARY KEY, ADD PRIMARY KEY (source, id);
+ALTER TABLE campaign_histories DROP INDEX idx_campaign_histories_campaign_version, ADD UNIQUE INDEX idx_campaign_histories_source_campaign_version (source, campaign_id, version);
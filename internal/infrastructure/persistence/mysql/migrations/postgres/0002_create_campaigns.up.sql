@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL DEFAULT 0,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT '',
+    budget DOUBLE PRECISION NOT NULL DEFAULT 0,
+    start_date TIMESTAMPTZ NULL,
+    end_date TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_campaigns_account_id ON campaigns (account_id);
+CREATE INDEX IF NOT EXISTS idx_campaigns_source ON campaigns (source);
@@ -0,0 +1,5 @@
+-- 上流から削除されたアカウント・キャンペーンを論理削除するためのカラム
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts (deleted_at);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns (deleted_at);
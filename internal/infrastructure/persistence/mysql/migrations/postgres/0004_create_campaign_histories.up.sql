@@ -0,0 +1,17 @@
+-- キャンペーンの変更履歴（SCD Type 2）
+CREATE TABLE IF NOT EXISTS campaign_histories (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    version INTEGER NOT NULL,
+    account_id BIGINT NOT NULL DEFAULT 0,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT '',
+    budget DOUBLE PRECISION NOT NULL DEFAULT 0,
+    start_date TIMESTAMPTZ NULL,
+    end_date TIMESTAMPTZ NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_histories_campaign_version ON campaign_histories (campaign_id, version);
@@ -0,0 +1,26 @@
+-- コマンドの実行履歴
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id BIGSERIAL PRIMARY KEY,
+    process VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT '',
+    account_ids TEXT NULL,
+    date_from VARCHAR(32) NOT NULL DEFAULT '',
+    date_to VARCHAR(32) NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NULL,
+    total_count INTEGER NOT NULL DEFAULT 0,
+    success_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    total_records INTEGER NOT NULL DEFAULT 0,
+    inserted_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    unchanged_count INTEGER NOT NULL DEFAULT 0,
+    removed_count INTEGER NOT NULL DEFAULT 0,
+    page_count INTEGER NOT NULL DEFAULT 0,
+    failed_account_ids TEXT NULL,
+    error_message TEXT NULL,
+    created_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_sync_runs_process ON sync_runs (process);
+CREATE INDEX IF NOT EXISTS idx_sync_runs_status ON sync_runs (status);
+CREATE INDEX IF NOT EXISTS idx_sync_runs_started_at ON sync_runs (started_at);
@@ -0,0 +1,8 @@
+-- 実行ごとにキャンペーンの同期が完了したアカウント（--resume で使用）
+CREATE TABLE IF NOT EXISTS sync_run_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    run_id BIGINT NOT NULL,
+    account_id BIGINT NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_run_checkpoints_run_account ON sync_run_checkpoints (run_id, account_id);
@@ -0,0 +1,9 @@
+-- アカウントごとのキャンペーンの差分同期の基準日時
+CREATE TABLE IF NOT EXISTS campaign_sync_watermarks (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL,
+    source VARCHAR(16) NOT NULL DEFAULT '',
+    high_water_mark TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_sync_watermarks_account_source ON campaign_sync_watermarks (account_id, source);
@@ -0,0 +1,4 @@
+-- 取得元が異なる同じIDのキャンペーンが保存されている場合は取り消せない
+DROP INDEX IF EXISTS idx_campaign_histories_source_campaign_version;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_histories_campaign_version ON campaign_histories (campaign_id, version);
+ALTER TABLE campaigns DROP CONSTRAINT campaigns_pkey, ADD PRIMARY KEY (id);
@@ -0,0 +1,7 @@
+-- 取得元ごとにキャンペーンIDの空間が異なるため、キャンペーンと変更履歴を (source, id) で識別する
+-- 取得元が記録されていないキャンペーンは api1 として扱う
+UPDATE campaigns SET source = 'api1' WHERE source = '';
+UPDATE campaign_histories SET source = 'api1' WHERE source = '';
+ALTER TABLE campaigns DROP CONSTRAINT campaigns_pkey, ADD PRIMARY KEY (source, id);
+DROP INDEX IF EXISTS idx_campaign_histories_campaign_version;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_histories_source_campaign_version ON campaign_histories (source, campaign_id, version);
@@ -0,0 +1,2 @@
+-- 取得元が異なる同じIDのアカウントが保存されている場合は取り消せない
+ALTER TABLE accounts DROP CONSTRAINT accounts_pkey, ADD PRIMARY KEY (id);
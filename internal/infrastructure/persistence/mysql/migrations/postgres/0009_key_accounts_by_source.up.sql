@@ -0,0 +1,4 @@
+-- 取得元ごとにアカウントIDの空間が異なるため、アカウントを (source, id) で識別する
+-- 取得元が記録されていないアカウントは api1 として扱う
+UPDATE accounts SET source = 'api1' WHERE source = '';
+ALTER TABLE accounts DROP CONSTRAINT accounts_pkey, ADD PRIMARY KEY (source, id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    api_key TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_source ON accounts (source);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL DEFAULT 0,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    budget REAL NOT NULL DEFAULT 0,
+    start_date DATETIME NULL,
+    end_date DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL
+);
+CREATE INDEX IF NOT EXISTS idx_campaigns_account_id ON campaigns (account_id);
+CREATE INDEX IF NOT EXISTS idx_campaigns_source ON campaigns (source);
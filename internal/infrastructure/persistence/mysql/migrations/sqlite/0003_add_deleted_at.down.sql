@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_campaigns_deleted_at;
+ALTER TABLE campaigns DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_accounts_deleted_at;
+ALTER TABLE accounts DROP COLUMN deleted_at;
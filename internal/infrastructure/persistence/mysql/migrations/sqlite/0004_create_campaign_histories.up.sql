@@ -0,0 +1,17 @@
+-- キャンペーンの変更履歴（SCD Type 2）
+CREATE TABLE IF NOT EXISTS campaign_histories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    account_id INTEGER NOT NULL DEFAULT 0,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    budget REAL NOT NULL DEFAULT 0,
+    start_date DATETIME NULL,
+    end_date DATETIME NULL,
+    valid_from DATETIME NOT NULL,
+    valid_to DATETIME NULL,
+    created_at DATETIME NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_histories_campaign_version ON campaign_histories (campaign_id, version);
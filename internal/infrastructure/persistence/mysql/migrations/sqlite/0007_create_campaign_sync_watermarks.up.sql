@@ -0,0 +1,9 @@
+-- アカウントごとのキャンペーンの差分同期の基準日時
+CREATE TABLE IF NOT EXISTS campaign_sync_watermarks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    high_water_mark DATETIME NOT NULL,
+    updated_at DATETIME NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_sync_watermarks_account_source ON campaign_sync_watermarks (account_id, source);
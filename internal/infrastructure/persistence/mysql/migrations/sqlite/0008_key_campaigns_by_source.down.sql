@@ -0,0 +1,24 @@
+-- 取得元が異なる同じIDのキャンペーンが保存されている場合は取り消せない
+DROP INDEX IF EXISTS idx_campaign_histories_source_campaign_version;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_histories_campaign_version ON campaign_histories (campaign_id, version);
+CREATE TABLE campaigns_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL DEFAULT 0,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    budget REAL NOT NULL DEFAULT 0,
+    start_date DATETIME NULL,
+    end_date DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL
+);
+INSERT INTO campaigns_old (id, account_id, source, name, status, budget, start_date, end_date, created_at, updated_at, deleted_at)
+SELECT id, account_id, source, name, status, budget, start_date, end_date, created_at, updated_at, deleted_at
+FROM campaigns;
+DROP TABLE campaigns;
+ALTER TABLE campaigns_old RENAME TO campaigns;
+CREATE INDEX IF NOT EXISTS idx_campaigns_account_id ON campaigns (account_id);
+CREATE INDEX IF NOT EXISTS idx_campaigns_source ON campaigns (source);
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns (deleted_at);
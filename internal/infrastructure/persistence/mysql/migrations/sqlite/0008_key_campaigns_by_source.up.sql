@@ -0,0 +1,28 @@
+-- 取得元ごとにキャンペーンIDの空間が異なるため、キャンペーンと変更履歴を (source, id) で識別する
+-- 取得元が記録されていないキャンペーンは api1 として扱う
+-- SQLite は主キーを変更できないため、テーブルを作り直す
+CREATE TABLE campaigns_new (
+    id INTEGER NOT NULL,
+    account_id INTEGER NOT NULL DEFAULT 0,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    budget REAL NOT NULL DEFAULT 0,
+    start_date DATETIME NULL,
+    end_date DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL,
+    PRIMARY KEY (source, id)
+);
+INSERT INTO campaigns_new (id, account_id, source, name, status, budget, start_date, end_date, created_at, updated_at, deleted_at)
+SELECT id, account_id, CASE source WHEN '' THEN 'api1' ELSE source END, name, status, budget, start_date, end_date, created_at, updated_at, deleted_at
+FROM campaigns;
+DROP TABLE campaigns;
+ALTER TABLE campaigns_new RENAME TO campaigns;
+CREATE INDEX IF NOT EXISTS idx_campaigns_account_id ON campaigns (account_id);
+CREATE INDEX IF NOT EXISTS idx_campaigns_source ON campaigns (source);
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns (deleted_at);
+UPDATE campaign_histories SET source = 'api1' WHERE source = '';
+DROP INDEX IF EXISTS idx_campaign_histories_campaign_version;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_histories_source_campaign_version ON campaign_histories (source, campaign_id, version);
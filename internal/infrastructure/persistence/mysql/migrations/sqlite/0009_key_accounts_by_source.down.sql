@@ -0,0 +1,18 @@
+-- 取得元が異なる同じIDのアカウントが保存されている場合は取り消せない
+CREATE TABLE accounts_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    api_key TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL
+);
+INSERT INTO accounts_old (id, source, name, status, api_key, created_at, updated_at, deleted_at)
+SELECT id, source, name, status, api_key, created_at, updated_at, deleted_at
+FROM accounts;
+DROP TABLE accounts;
+ALTER TABLE accounts_old RENAME TO accounts;
+CREATE INDEX IF NOT EXISTS idx_accounts_source ON accounts (source);
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts (deleted_at);
@@ -0,0 +1,21 @@
+-- 取得元ごとにアカウントIDの空間が異なるため、アカウントを (source, id) で識別する
+-- 取得元が記録されていないアカウントは api1 として扱う
+-- SQLite は主キーを変更できないため、テーブルを作り直す
+CREATE TABLE accounts_new (
+    id INTEGER NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    api_key TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL,
+    PRIMARY KEY (source, id)
+);
+INSERT INTO accounts_new (id, source, name, status, api_key, created_at, updated_at, deleted_at)
+SELECT id, CASE source WHEN '' THEN 'api1' ELSE source END, name, status, api_key, created_at, updated_at, deleted_at
+FROM accounts;
+DROP TABLE accounts;
+ALTER TABLE accounts_new RENAME TO accounts;
+CREATE INDEX IF NOT EXISTS idx_accounts_source ON accounts (source);
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts (deleted_at);
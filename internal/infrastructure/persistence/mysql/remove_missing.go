@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"gorm.io/gorm"
+)
+
+// removeMissing は scope で絞り込んだ行のうち、keepIDs に含まれない行を batchSize 件ずつ削除し、削除した行のIDを返します
+// 取得元ごとにIDの空間が異なるため、削除も scope で絞り込んだ行に限定します
+// prune が false の場合は論理削除（deleted_at を設定）し、true の場合は論理削除済みの行も含めて物理削除します
+func removeMissing[T any](tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, keepIDs []uint, prune bool, batchSize int) ([]uint, error) {
+	// 物理削除の場合は論理削除済みの行も対象にする
+	db := func() *gorm.DB {
+		if prune {
+			return tx.Unscoped()
+		}
+		return tx
+	}
+
+	var ids []uint
+	if err := scope(db().Model(new(T))).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	keep := make(map[uint]struct{}, len(keepIDs))
+	for _, id := range keepIDs {
+		keep[id] = struct{}{}
+	}
+
+	missing := make([]uint, 0)
+	for _, id := range ids {
+		if _, ok := keep[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	for start := 0; start < len(missing); start += batchSize {
+		end := min(start+batchSize, len(missing))
+		if err := scope(db()).Where("id IN ?", missing[start:end]).Delete(new(T)).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return missing, nil
+}
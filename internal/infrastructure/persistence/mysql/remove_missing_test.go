@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+func TestCampaignRemoveMissingByAccountID(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	cfg := &config.Config{}
+	cfg.Database.BatchSize = 1
+	repo := NewCampaignRepository(db, cfg)
+	ctx := context.Background()
+
+	_, err := repo.SaveAll(ctx, []entity.Campaign{
+		{Source: entity.SourceAPI1, ID: 1, AccountID: 1, Name: "A"},
+		{Source: entity.SourceAPI1, ID: 2, AccountID: 1, Name: "B"},
+		{Source: entity.SourceAPI1, ID: 3, AccountID: 1, Name: "C"},
+		{Source: entity.SourceAPI1, ID: 4, AccountID: 2, Name: "D"},
+	})
+	assert.NoError(t, err)
+
+	// アカウント1の上流には ID 1 のみ残っている（アカウント2のキャンペーンは対象外）
+	removed, err := repo.RemoveMissingByAccountID(ctx, entity.SourceAPI1, 1, []uint{1}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	campaigns, err := repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, campaigns, 2)
+
+	// 論理削除した行は残っている
+	var count int64
+	assert.NoError(t, db.Unscoped().Model(&entity.Campaign{}).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+
+	// 上流に再び現れたキャンペーンは復元し、更新として数える
+	result, err := repo.SaveAll(ctx, []entity.Campaign{{Source: entity.SourceAPI1, ID: 2, AccountID: 1, Name: "B2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	campaign, err := repo.FindByID(ctx, entity.SourceAPI1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "B2", campaign.Name)
+
+	// 物理削除では論理削除済みの行も削除する
+	removed, err = repo.RemoveMissingByAccountID(ctx, entity.SourceAPI1, 1, []uint{1}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	assert.NoError(t, db.Unscoped().Model(&entity.Campaign{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestAccountRemoveMissingBySource(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewAccountRepository(db, nil)
+	ctx := context.Background()
+
+	_, err := repo.SaveAll(ctx, []entity.Account{
+		{ID: 1, Source: entity.SourceAPI1, Name: "A"},
+		{ID: 2, Source: entity.SourceAPI1, Name: "B"},
+		{ID: 2, Source: entity.SourceAPI2, Name: "C"},
+	})
+	assert.NoError(t, err)
+
+	// 同じIDでも api2 のアカウントは対象外
+	removed, err := repo.RemoveMissingBySource(ctx, entity.SourceAPI1, []uint{1}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	accounts, err := repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	account, err := repo.FindByID(ctx, entity.SourceAPI2, 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, account)
+
+	// 単一の保存でも論理削除済みのアカウントを復元する
+	assert.NoError(t, repo.Save(ctx, entity.Account{ID: 2, Source: entity.SourceAPI1, Name: "復元"}))
+	account, err = repo.FindByID(ctx, entity.SourceAPI1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "復元", account.Name)
+}
@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// SyncRunRepositoryImpl はSyncRunRepositoryインターフェースの実装です
+// 他のリポジトリと同様に、コンテキストにトランザクションがあればそのトランザクションで読み書きします
+// 実行履歴は同期処理のロールバックに巻き込まれないよう、ユースケースがトランザクションの外で記録します
+// チェックポイントは同期したデータと整合させるため、同期と同じトランザクションで記録します
+type SyncRunRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSyncRunRepository は新しいSyncRunRepositoryImplインスタンスを作成します
+func NewSyncRunRepository(db *gorm.DB) repository.SyncRunRepository {
+	return &SyncRunRepositoryImpl{db: db}
+}
+
+// Create は実行履歴を保存します
+func (r *SyncRunRepositoryImpl) Create(ctx context.Context, run *entity.SyncRun) error {
+	result := conn(ctx, r.db).Create(run)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("process", run.Process).Msg("実行履歴の保存に失敗しました")
+		return result.Error
+	}
+	log.Debug().Uint("id", run.ID).Msg("実行履歴を保存しました")
+	return nil
+}
+
+// Update は保存済みの実行履歴を更新します（作成日時は更新しません）
+func (r *SyncRunRepositoryImpl) Update(ctx context.Context, run *entity.SyncRun) error {
+	result := conn(ctx, r.db).Omit("created_at").Save(run)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Uint("id", run.ID).Msg("実行履歴の更新に失敗しました")
+		return result.Error
+	}
+	log.Debug().Uint("id", run.ID).Str("status", run.Status).Msg("実行履歴を更新しました")
+	return nil
+}
+
+// FindByID は指定されたIDの実行履歴を取得します（見つからない場合は nil）
+func (r *SyncRunRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.SyncRun, error) {
+	var run entity.SyncRun
+	result := conn(ctx, r.db).First(&run, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			log.Debug().Uint("id", id).Msg("実行履歴が見つかりませんでした")
+			return nil, nil
+		}
+		log.Error().Err(result.Error).Uint("id", id).Msg("実行履歴の取得に失敗しました")
+		return nil, result.Error
+	}
+	return &run, nil
+}
+
+// Find は検索条件に一致する実行履歴を開始時刻の新しい順に取得します
+func (r *SyncRunRepositoryImpl) Find(ctx context.Context, filter repository.SyncRunFilter) ([]entity.SyncRun, error) {
+	query := conn(ctx, r.db).Order("started_at DESC").Order("id DESC")
+	if filter.Process != "" {
+		query = query.Where("process LIKE ? ESCAPE '!'", likeEscaper.Replace(filter.Process)+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("started_at < ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var runs []entity.SyncRun
+	if err := query.Find(&runs).Error; err != nil {
+		log.Error().Err(err).Msg("実行履歴の一覧の取得に失敗しました")
+		return nil, err
+	}
+	return runs, nil
+}
+
+// SaveCheckpoints は指定された実行でキャンペーンの同期が完了したアカウントを記録します（記録済みのアカウントは無視します）
+func (r *SyncRunRepositoryImpl) SaveCheckpoints(ctx context.Context, runID uint, accountIDs []uint) error {
+	if len(accountIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	checkpoints := make([]entity.SyncRunCheckpoint, len(accountIDs))
+	for i, accountID := range accountIDs {
+		checkpoints[i] = entity.SyncRunCheckpoint{RunID: runID, AccountID: accountID, CompletedAt: now}
+	}
+
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(checkpoints, DefaultBatchSize)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Uint("run_id", runID).Msg("チェックポイントの記録に失敗しました")
+		return result.Error
+	}
+	log.Debug().Uint("run_id", runID).Uints("account_ids", accountIDs).Msg("チェックポイントを記録しました")
+	return nil
+}
+
+// FindCheckpointAccountIDs は指定された実行でキャンペーンの同期が完了したアカウントのIDを取得します
+func (r *SyncRunRepositoryImpl) FindCheckpointAccountIDs(ctx context.Context, runID uint) ([]uint, error) {
+	var accountIDs []uint
+	result := conn(ctx, r.db).Model(&entity.SyncRunCheckpoint{}).
+		Where("run_id = ?", runID).
+		Order("account_id").
+		Pluck("account_id", &accountIDs)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Uint("run_id", runID).Msg("チェックポイントの取得に失敗しました")
+		return nil, result.Error
+	}
+	return accountIDs, nil
+}
+
+// likeEscaper はLIKE検索のワイルドカード文字をエスケープします
+// ダイアレクトによって文字列リテラルのバックスラッシュの扱いが異なるため、エスケープ文字には ! を使用します
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+func TestSyncRunRepository(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewSyncRunRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	runs := []entity.SyncRun{
+		{Process: "account sync", Status: "success", StartedAt: base, FinishedAt: base.Add(time.Minute)},
+		{Process: "campaign sync", Status: "failed", StartedAt: base.Add(time.Hour), ErrorMessage: "タイムアウト"},
+		{Process: "campaign sync --source api2", Status: "success", StartedAt: base.Add(2 * time.Hour)},
+		{Process: "campaign_sync", Status: "success", StartedAt: base.Add(3 * time.Hour)},
+	}
+	for i := range runs {
+		assert.NoError(t, repo.Create(ctx, &runs[i]))
+		assert.NotZero(t, runs[i].ID)
+	}
+
+	// 開始時刻の新しい順に返す
+	found, err := repo.Find(ctx, repository.SyncRunFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, found, 4)
+	assert.Equal(t, runs[3].ID, found[0].ID)
+
+	// コマンドは前方一致（_ はワイルドカードとして扱わない）
+	found, err = repo.Find(ctx, repository.SyncRunFilter{Process: "campaign sync"})
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	found, err = repo.Find(ctx, repository.SyncRunFilter{Process: "campaign sync", Status: "failed"})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "タイムアウト", found[0].ErrorMessage)
+
+	from := base.Add(time.Hour)
+	to := base.Add(3 * time.Hour)
+	found, err = repo.Find(ctx, repository.SyncRunFilter{From: &from, To: &to, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, runs[2].ID, found[0].ID)
+
+	run, err := repo.FindByID(ctx, runs[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, run.Duration())
+
+	run, err = repo.FindByID(ctx, 999)
+	assert.NoError(t, err)
+	assert.Nil(t, run)
+}
+
+func TestSyncRunRepositoryUpdate(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewSyncRunRepository(db)
+	ctx := context.Background()
+
+	run := entity.SyncRun{Process: "campaign sync", Status: "running", StartedAt: time.Now()}
+	assert.NoError(t, repo.Create(ctx, &run))
+	createdAt := run.CreatedAt
+
+	// 作成日時を持たない実行履歴で更新しても作成日時は変わらない
+	assert.NoError(t, repo.Update(ctx, &entity.SyncRun{
+		ID:           run.ID,
+		Process:      run.Process,
+		Status:       "failed",
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.StartedAt.Add(time.Second),
+		ErrorMessage: "中断",
+	}))
+
+	found, err := repo.FindByID(ctx, run.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", found.Status)
+	assert.Equal(t, "中断", found.ErrorMessage)
+	assert.WithinDuration(t, createdAt, found.CreatedAt, time.Millisecond)
+}
+
+func TestSyncRunRepositoryCheckpoints(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewSyncRunRepository(db)
+	txManager := &Database{DB: db}
+	ctx := context.Background()
+
+	// 記録済みのアカウントは無視する
+	assert.NoError(t, repo.SaveCheckpoints(ctx, 1, []uint{3, 1}))
+	assert.NoError(t, repo.SaveCheckpoints(ctx, 1, []uint{1, 2}))
+	assert.NoError(t, repo.SaveCheckpoints(ctx, 2, []uint{5}))
+
+	ids, err := repo.FindCheckpointAccountIDs(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3}, ids)
+
+	// トランザクションをロールバックした場合はチェックポイントも残らない
+	errSync := errors.New("キャンペーンの保存に失敗")
+	err = txManager.Do(ctx, func(ctx context.Context) error {
+		if err := repo.SaveCheckpoints(ctx, 2, []uint{6}); err != nil {
+			return err
+		}
+		return errSync
+	})
+	assert.ErrorIs(t, err, errSync)
+
+	ids, err = repo.FindCheckpointAccountIDs(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{5}, ids)
+
+	ids, err = repo.FindCheckpointAccountIDs(ctx, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestSyncRunRepositoryTransaction(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewSyncRunRepository(db)
+	txManager := &Database{DB: db}
+	ctx := context.Background()
+
+	// 実行履歴もコンテキストのトランザクションで書き込むため、ロールバックした場合は残らない
+	errSync := errors.New("同期に失敗")
+	var runID uint
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		run := entity.SyncRun{Process: "campaign", Status: "running", StartedAt: time.Now()}
+		if err := repo.Create(ctx, &run); err != nil {
+			return err
+		}
+		runID = run.ID
+		return errSync
+	})
+	assert.ErrorIs(t, err, errSync)
+
+	found, err := repo.FindByID(ctx, runID)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
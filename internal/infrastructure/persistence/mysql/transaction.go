@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey はコンテキストに実行中のトランザクションを格納するキーです
+type txKey struct{}
+
+// Do は fn を1つのトランザクションで実行します（repository.TransactionManager の実装）
+// fn に渡すコンテキストにトランザクションを格納し、各リポジトリはそのトランザクションで読み書きします
+// すでにトランザクション内で呼び出された場合は、セーブポイントを作成して fn を実行します
+// fn がエラーを返した場合はセーブポイントまでロールバックし、外側のトランザクションは継続できます
+func (db *Database) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		})
+	}
+
+	return db.GetWriter().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// txFromContext はコンテキストに格納されたトランザクションを返します
+func txFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// conn はコンテキストにトランザクションがあればそのトランザクションを、なければ db を ctx 付きで返します
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// reader はリードオンリー操作用の接続を返します（トランザクション内ではトランザクションの接続を使用します）
+func (db *Database) reader(ctx context.Context) *gorm.DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.GetReader().WithContext(ctx)
+}
+
+// writer はライト操作用の接続を返します（トランザクション内ではトランザクションの接続を使用します）
+func (db *Database) writer(ctx context.Context) *gorm.DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.GetWriter().WithContext(ctx)
+}
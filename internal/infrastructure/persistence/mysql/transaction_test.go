@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+)
+
+func TestDatabaseDo(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	txManager := &Database{DB: db}
+	accountRepo := NewAccountRepository(db, nil)
+	campaignRepo := NewCampaignRepository(db, nil)
+	ctx := context.Background()
+
+	// 途中で失敗した場合はアカウントとキャンペーンの両方をロールバックする
+	errSync := errors.New("キャンペーン同期の失敗")
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := accountRepo.SaveAll(ctx, []entity.Account{{Source: entity.SourceAPI1, ID: 1, Name: "A"}}); err != nil {
+			return err
+		}
+		// トランザクション内では書き込んだアカウントを読み込める
+		account, err := accountRepo.FindByID(ctx, entity.SourceAPI1, 1)
+		if err != nil {
+			return err
+		}
+		assert.NotNil(t, account)
+
+		if _, err := campaignRepo.SaveAll(ctx, []entity.Campaign{{Source: entity.SourceAPI1, ID: 1, AccountID: 1, Name: "C"}}); err != nil {
+			return err
+		}
+		return errSync
+	})
+	assert.ErrorIs(t, err, errSync)
+
+	accounts, err := accountRepo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, accounts)
+	campaigns, err := campaignRepo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, campaigns)
+
+	// 成功した場合は両方をコミットする
+	err = txManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := accountRepo.SaveAll(ctx, []entity.Account{{Source: entity.SourceAPI1, ID: 1, Name: "A"}}); err != nil {
+			return err
+		}
+		_, err := campaignRepo.SaveAll(ctx, []entity.Campaign{{Source: entity.SourceAPI1, ID: 1, AccountID: 1, Name: "C"}})
+		return err
+	})
+	assert.NoError(t, err)
+
+	accounts, err = accountRepo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+	histories, err := campaignRepo.FindHistoryByCampaignID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 1)
+}
+
+func TestDatabaseDoCancelled(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	txManager := &Database{DB: db}
+	accountRepo := NewAccountRepository(db, nil)
+
+	// シグナルの受信などで処理中にキャンセルされた場合は、書き込みをロールバックする
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := accountRepo.SaveAll(ctx, []entity.Account{{Source: entity.SourceAPI1, ID: 1, Name: "A"}}); err != nil {
+			return err
+		}
+		cancel()
+		_, err := accountRepo.SaveAll(ctx, []entity.Account{{Source: entity.SourceAPI1, ID: 2, Name: "B"}})
+		return err
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	accounts, err := accountRepo.FindAll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, accounts)
+}
+
+func TestDatabaseDoNested(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	txManager := &Database{DB: db}
+	campaignRepo := NewCampaignRepository(db, nil)
+	runRepo := NewSyncRunRepository(db)
+	ctx := context.Background()
+	updatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// master --atomic --continue-on-error と同様に、アカウントごとの保存を入れ子のトランザクションで実行し、
+	// 失敗したアカウントを読み飛ばして外側のトランザクションをコミットする
+	errSave := errors.New("チェックポイントの記録に失敗")
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		for _, accountID := range []uint{1, 2} {
+			err := txManager.Do(ctx, func(ctx context.Context) error {
+				campaign := entity.Campaign{Source: entity.SourceAPI1, ID: accountID * 10, AccountID: accountID, Name: "C", UpdatedAt: updatedAt}
+				if _, err := campaignRepo.SaveAll(ctx, []entity.Campaign{campaign}); err != nil {
+					return err
+				}
+				if err := campaignRepo.SaveWatermark(ctx, accountID, entity.SourceAPI1, updatedAt); err != nil {
+					return err
+				}
+				if accountID == 2 {
+					return errSave
+				}
+				return runRepo.SaveCheckpoints(ctx, 1, []uint{accountID})
+			})
+			if accountID == 2 {
+				assert.ErrorIs(t, err, errSave)
+				continue
+			}
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// 失敗したアカウントの書き込みのみがロールバックされ、キャンペーン・基準日時・チェックポイントが一致する
+	campaigns, err := campaignRepo.FindAll(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, campaigns, 1) {
+		assert.Equal(t, uint(1), campaigns[0].AccountID)
+	}
+	watermarks, err := campaignRepo.FindWatermarks(ctx, entity.SourceAPI1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, watermarkAccountIDs(watermarks))
+	ids, err := runRepo.FindCheckpointAccountIDs(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, ids)
+}
+
+func watermarkAccountIDs(m map[uint]time.Time) []uint {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
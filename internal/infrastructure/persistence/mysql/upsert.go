@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// DefaultBatchSize は一括保存で1回のSQLに含める件数のデフォルト値です
+const DefaultBatchSize = 500
+
+// batchSizeFromConfig は設定から一括保存の件数を返します（未設定の場合は DefaultBatchSize）
+func batchSizeFromConfig(cfg *config.Config) int {
+	if cfg == nil || cfg.Database.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return cfg.Database.BatchSize
+}
+
+// sourceKey は取得元とIDの組み合わせです
+// 取得元ごとにIDの空間が異なるため、アップサートや重複の除去はこの組み合わせで行います
+type sourceKey struct {
+	source string
+	id     uint
+}
+
+// upsertAll は rows を取得元ごとに batchSize 件ずつ主キー（source, id）でアップサートします
+// SQLはダイアレクトに応じて INSERT ... ON DUPLICATE KEY UPDATE（MySQL）または
+// INSERT ... ON CONFLICT (source, id) DO UPDATE（PostgreSQL, SQLite）になります
+// 新規登録と更新の件数は、チャンクごとに主キーで既存のIDを確認して数えます（テーブル全体は読み込みません）
+func upsertAll[T any](tx *gorm.DB, rows []T, key func(T) sourceKey, updateColumns []string, batchSize int) (repository.UpsertResult, error) {
+	var result repository.UpsertResult
+	rows = dedupeByKey(rows, key)
+
+	onConflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}, {Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(updateColumns),
+	}
+
+	for _, group := range groupBySource(rows, key) {
+		for start := 0; start < len(group); start += batchSize {
+			end := min(start+batchSize, len(group))
+			chunk := group[start:end]
+
+			source := key(chunk[0]).source
+			ids := make([]uint, len(chunk))
+			for i, row := range chunk {
+				ids[i] = key(row).id
+			}
+
+			// 論理削除済みの行も復元して更新するため、削除済みの行も既存として数える
+			var existing int64
+			if err := tx.Unscoped().Model(new(T)).Where("source = ? AND id IN ?", source, ids).Count(&existing).Error; err != nil {
+				return result, err
+			}
+
+			if err := tx.Clauses(onConflict).Create(&chunk).Error; err != nil {
+				return result, err
+			}
+
+			result.Add(repository.UpsertResult{
+				Inserted: len(chunk) - int(existing),
+				Updated:  int(existing),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// groupBySource は rows を取得元ごとに分け、取得元が最初に現れた順に返します
+func groupBySource[T any](rows []T, key func(T) sourceKey) [][]T {
+	index := map[string]int{}
+	var groups [][]T
+	for _, row := range rows {
+		source := key(row).source
+		i, ok := index[source]
+		if !ok {
+			i = len(groups)
+			index[source] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], row)
+	}
+	return groups
+}
+
+// dedupeByKey は同じキーの行が複数ある場合に最後の行だけを残します
+// ON CONFLICT は1つのSQLで同じ行を2回更新できないため、アップサートの前に重複を取り除きます
+func dedupeByKey[T any, K comparable](rows []T, key func(T) K) []T {
+	last := make(map[K]int, len(rows))
+	for i, row := range rows {
+		last[key(row)] = i
+	}
+	if len(last) == len(rows) {
+		return rows
+	}
+
+	deduped := make([]T, 0, len(last))
+	for i, row := range rows {
+		if last[key(row)] == i {
+			deduped = append(deduped, row)
+		}
+	}
+	return deduped
+}
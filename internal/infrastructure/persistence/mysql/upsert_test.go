@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mysqlDriver "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
+)
+
+// newMigratedSQLiteDB はマイグレーションを適用したインメモリのSQLiteデータベースを作成します
+func newMigratedSQLiteDB(t *testing.T) *gorm.DB {
+	db := newSQLiteDB(t)
+	migrator, err := NewSchemaMigrationRepository(db)
+	assert.NoError(t, err)
+	_, err = migrator.Up(context.Background(), 0)
+	assert.NoError(t, err)
+	return db
+}
+
+func TestCampaignSaveAllUpsert(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	cfg := &config.Config{}
+	cfg.Database.BatchSize = 2
+	repo := NewCampaignRepository(db, cfg)
+	ctx := context.Background()
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, db.Create(&[]entity.Campaign{
+		{ID: 1, AccountID: 1, Name: "旧1", CreatedAt: createdAt},
+		{ID: 2, AccountID: 1, Name: "旧2", CreatedAt: createdAt},
+	}).Error)
+
+	// ID 1, 2 は更新、3〜5 は新規（ID 5 は重複しているため最後の行を保存）
+	result, err := repo.SaveAll(ctx, []entity.Campaign{
+		{ID: 1, AccountID: 1, Name: "新1"},
+		{ID: 2, AccountID: 1, Name: "新2"},
+		{ID: 3, AccountID: 1, Name: "新3"},
+		{ID: 4, AccountID: 2, Name: "新4"},
+		{ID: 5, AccountID: 2, Name: "重複"},
+		{ID: 5, AccountID: 2, Name: "新5"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Inserted)
+	assert.Equal(t, 2, result.Updated)
+
+	var campaigns []entity.Campaign
+	assert.NoError(t, db.Order("id").Find(&campaigns).Error)
+	assert.Len(t, campaigns, 5)
+	assert.Equal(t, "新1", campaigns[0].Name)
+	assert.Equal(t, "新5", campaigns[4].Name)
+	// 作成日時は更新しない
+	assert.True(t, createdAt.Equal(campaigns[0].CreatedAt))
+}
+
+func TestAccountSaveAllUpsert(t *testing.T) {
+	db := newMigratedSQLiteDB(t)
+	repo := NewAccountRepository(db, nil)
+	ctx := context.Background()
+
+	result, err := repo.SaveAll(ctx, []entity.Account{
+		{Source: entity.SourceAPI1, ID: 1, Name: "A", APIKey: "key1"},
+		{Source: entity.SourceAPI1, ID: 2, Name: "B", APIKey: "key2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Inserted)
+
+	result, err = repo.SaveAll(ctx, []entity.Account{
+		{Source: entity.SourceAPI1, ID: 2, Name: "B2", APIKey: "key2"},
+		{Source: entity.SourceAPI1, ID: 3, Name: "C"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 1, result.Updated)
+
+	account, err := repo.FindByID(ctx, entity.SourceAPI1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "B2", account.Name)
+
+	// 取得元が異なれば同じIDでも別のアカウントとして保存し、api1 のアカウントを上書きしない
+	result, err = repo.SaveAll(ctx, []entity.Account{{Source: entity.SourceAPI2, ID: 1, Name: "顧客"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+
+	account, err = repo.FindByID(ctx, entity.SourceAPI1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "A", account.Name)
+	assert.Equal(t, "key1", account.APIKey)
+	account, err = repo.FindByID(ctx, entity.SourceAPI2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "顧客", account.Name)
+}
+
+func TestUpsertSQLMySQL(t *testing.T) {
+	// MySQLでは ON DUPLICATE KEY UPDATE を使用する
+	db, err := gorm.Open(mysqlDriver.New(mysqlDriver.Config{SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	assert.NoError(t, err)
+
+	var statements []string
+	assert.NoError(t, db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	campaigns := []entity.Campaign{{ID: 1}, {ID: 2}, {ID: 3}}
+	_, err = upsertAll(db, campaigns, campaignKey, campaignUpdateColumns, 2)
+	assert.NoError(t, err)
+
+	// 2件ずつ2回に分けて実行する
+	assert.Len(t, statements, 2)
+	assert.Contains(t, statements[0], "ON DUPLICATE KEY UPDATE")
+	assert.Contains(t, statements[0], "`budget`=VALUES(`budget`)")
+	assert.NotContains(t, statements[0], "`created_at`=VALUES")
+}
@@ -3,11 +3,13 @@ package secrets
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/rs/zerolog/log"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
@@ -19,6 +21,12 @@ type Manager interface {
 	GetSecret(ctx context.Context, secretID string) (string, error)
 }
 
+// Writer はシークレットの書き込みのインターフェースです
+type Writer interface {
+	// PutSecret はシークレットの値を保存します（存在しない場合は作成します）
+	PutSecret(ctx context.Context, secretID, value string) error
+}
+
 // DatabaseSecret はデータベース接続情報を表します
 type DatabaseSecret struct {
 	Username string `json:"username"`
@@ -83,6 +91,34 @@ func (sm *AWSSecretsManager) GetSecret(ctx context.Context, secretID string) (st
 	return secretString, nil
 }
 
+// PutSecret は指定されたシークレットIDに値を保存します
+// シークレットが存在しない場合は新しく作成します
+func (sm *AWSSecretsManager) PutSecret(ctx context.Context, secretID, value string) error {
+	// シークレットが有効でない場合はエラーを返す
+	if !sm.config.AWS.Secrets.Enabled {
+		return fmt.Errorf("Secret Managerは無効に設定されています")
+	}
+
+	_, err := sm.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretID),
+		SecretString: aws.String(value),
+	})
+
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		log.Info().Str("secret_id", secretID).Msg("シークレットが存在しないため作成します")
+		_, err = sm.client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+			Name:         aws.String(secretID),
+			SecretString: aws.String(value),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("シークレットの保存に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
 // GetDatabaseSecret はデータベース接続情報を取得します
 func (sm *AWSSecretsManager) GetDatabaseSecret(ctx context.Context, secretID string) (*DatabaseSecret, error) {
 	secretValue, err := sm.GetSecret(ctx, secretID)
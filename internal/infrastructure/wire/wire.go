@@ -9,7 +9,9 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/api/externalapi1"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/api/externalapi2"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
 	httpClient "github.com/yuru-sha/go-cli-ddd/internal/infrastructure/http"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/notification"
@@ -35,20 +37,32 @@ func InitializeApp(params AppParams) (*cobra.Command, error) {
 		// シークレットマネージャー
 		secrets.NewAWSSecretsManager,
 		ProvideSecretsManager,
+		ProvideSecretsWriter,
 
 		// データベース
 		mysql.NewDatabase,
 		ProvideDatabaseConnection,
 		mysql.NewAccountRepository,
 		mysql.NewCampaignRepository,
+		mysql.NewSchemaMigrationRepository,
+		ProvideTransactionManager,
+		mysql.NewSyncRunRepository,
 
 		// HTTP
 		httpClient.NewHTTPClient,
 
 		// ExternalAPI1
+		externalapi1.NewAPIClient,
 		externalapi1.NewAccountRepository,
 		externalapi1.NewCampaignRepository,
 
+		// ExternalAPI2
+		externalapi2.NewTokenStore,
+		externalapi2.NewClient,
+		externalapi2.NewAccountRepository,
+		externalapi2.NewCampaignRepository,
+		externalapi2.NewAuthorizer,
+
 		// 通知
 		notification.NewRepository,
 
@@ -56,12 +70,18 @@ func InitializeApp(params AppParams) (*cobra.Command, error) {
 		usecase.NewAccountUseCase,
 		usecase.NewCampaignUseCase,
 		usecase.NewMasterUseCase,
+		usecase.NewAuthUseCase,
+		usecase.NewMigrationUseCase,
+		usecase.NewRunUseCase,
 
 		// コマンド
 		cli.NewRootCommand,
 		cli.NewAccountCommand,
 		cli.NewCampaignCommand,
 		cli.NewMasterCommand,
+		cli.NewAuthCommand,
+		cli.NewMigrateCommand,
+		cli.NewRunsCommand,
 
 		// ルートコマンドの初期化
 		ProvideRootCommand,
@@ -94,20 +114,39 @@ func ProvideSecretsManager(sm *secrets.AWSSecretsManager) secrets.Manager {
 	return sm
 }
 
+// ProvideSecretsWriter はシークレットの書き込み用のWriterインターフェースを提供します
+func ProvideSecretsWriter(sm *secrets.AWSSecretsManager) secrets.Writer {
+	return sm
+}
+
 // ProvideDatabaseConnection はデータベース接続を提供します
 func ProvideDatabaseConnection(db *mysql.Database) *gorm.DB {
 	return db.DB
 }
 
+// ProvideTransactionManager はデータベース接続のトランザクションを使用するTransactionManagerを提供します
+func ProvideTransactionManager(db *mysql.Database) repository.TransactionManager {
+	return db
+}
+
 // ProvideRootCommand はルートコマンドを提供します
+// 起動時の自動マイグレーションは、migrate コマンド以外のコマンドの実行前に行います
 func ProvideRootCommand(
+	db *mysql.Database,
 	rootCmd *cli.RootCommand,
 	accountCmd *cli.AccountCommand,
 	campaignCmd *cli.CampaignCommand,
 	masterCmd *cli.MasterCommand,
+	authCmd *cli.AuthCommand,
+	migrateCmd *cli.MigrateCommand,
+	runsCmd *cli.RunsCommand,
 ) (*cobra.Command, error) {
 	rootCmd.Cmd.AddCommand(accountCmd.Cmd)
 	rootCmd.Cmd.AddCommand(campaignCmd.Cmd)
 	rootCmd.Cmd.AddCommand(masterCmd.Cmd)
+	rootCmd.Cmd.AddCommand(authCmd.Cmd)
+	rootCmd.Cmd.AddCommand(migrateCmd.Cmd)
+	rootCmd.Cmd.AddCommand(runsCmd.Cmd)
+	rootCmd.SetAutoMigrate(db.AutoMigrate, migrateCmd.Cmd)
 	return rootCmd.Cmd, nil
 }
@@ -9,7 +9,9 @@ package wire
 import (
 	"github.com/spf13/cobra"
 	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/api/externalapi1"
+	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/api/externalapi2"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/config"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/http"
 	"github.com/yuru-sha/go-cli-ddd/internal/infrastructure/notification"
@@ -23,7 +25,6 @@ import (
 
 // InitializeApp はアプリケーションを初期化します
 func InitializeApp(params AppParams) (*cobra.Command, error) {
-	rootCommand := cli.NewRootCommand()
 	options := ProvideConfigOptions(params)
 	configConfig, err := config.LoadConfig(options)
 	if err != nil {
@@ -33,8 +34,9 @@ func InitializeApp(params AppParams) (*cobra.Command, error) {
 	if err != nil {
 		return nil, err
 	}
+	rootCommand := cli.NewRootCommand()
 	db := ProvideDatabaseConnection(database)
-	mySQLAccountRepository := mysql.NewAccountRepository(db)
+	mySQLAccountRepository := mysql.NewAccountRepository(db, configConfig)
 	httpConfig := ProvideHTTPConfig(configConfig)
 	client := http.NewHTTPClient(httpConfig)
 	awsSecretsManager, err := secrets.NewAWSSecretsManager(configConfig)
@@ -42,17 +44,51 @@ func InitializeApp(params AppParams) (*cobra.Command, error) {
 		return nil, err
 	}
 	manager := ProvideSecretsManager(awsSecretsManager)
-	externalAPI1AccountRepository := externalapi1.NewAccountRepository(configConfig, client, manager)
+	apiClient := externalapi1.NewAPIClient(configConfig, client, manager)
+	externalAPI1AccountRepository, err := externalapi1.NewAccountRepository(configConfig, apiClient)
+	if err != nil {
+		return nil, err
+	}
+	tokenStore, err := externalapi2.NewTokenStore(configConfig, manager)
+	if err != nil {
+		return nil, err
+	}
+	externalapi2Client := externalapi2.NewClient(configConfig, client, manager, tokenStore)
+	externalAPI2AccountRepository, err := externalapi2.NewAccountRepository(configConfig, externalapi2Client)
+	if err != nil {
+		return nil, err
+	}
+	syncRunRepository := mysql.NewSyncRunRepository(db)
 	notificationRepository := notification.NewRepository(configConfig)
-	accountUseCase := usecase.NewAccountUseCase(mySQLAccountRepository, externalAPI1AccountRepository, notificationRepository)
+	accountUseCase := usecase.NewAccountUseCase(mySQLAccountRepository, externalAPI1AccountRepository, externalAPI2AccountRepository, syncRunRepository, notificationRepository)
 	accountCommand := cli.NewAccountCommand(accountUseCase)
-	mySQLCampaignRepository := mysql.NewCampaignRepository(db)
-	externalAPI1CampaignRepository := externalapi1.NewCampaignRepository(configConfig, client, manager)
-	campaignUseCase := usecase.NewCampaignUseCase(mySQLCampaignRepository, externalAPI1CampaignRepository, mySQLAccountRepository)
+	mySQLCampaignRepository := mysql.NewCampaignRepository(db, configConfig)
+	externalAPI1CampaignRepository, err := externalapi1.NewCampaignRepository(configConfig, apiClient)
+	if err != nil {
+		return nil, err
+	}
+	externalAPI2CampaignRepository, err := externalapi2.NewCampaignRepository(configConfig, externalapi2Client)
+	if err != nil {
+		return nil, err
+	}
+	transactionManager := ProvideTransactionManager(database)
+	campaignUseCase := usecase.NewCampaignUseCase(mySQLCampaignRepository, externalAPI1CampaignRepository, externalAPI2CampaignRepository, mySQLAccountRepository, transactionManager, syncRunRepository, notificationRepository)
 	campaignCommand := cli.NewCampaignCommand(campaignUseCase)
-	masterUseCase := usecase.NewMasterUseCase(accountUseCase, campaignUseCase)
+	masterUseCase := usecase.NewMasterUseCase(accountUseCase, campaignUseCase, transactionManager, syncRunRepository, notificationRepository)
 	masterCommand := cli.NewMasterCommand(masterUseCase)
-	command, err := ProvideRootCommand(rootCommand, accountCommand, campaignCommand, masterCommand)
+	writer := ProvideSecretsWriter(awsSecretsManager)
+	externalAPI2Authorizer := externalapi2.NewAuthorizer(configConfig, externalapi2Client, writer)
+	authUseCase := usecase.NewAuthUseCase(externalAPI2Authorizer)
+	authCommand := cli.NewAuthCommand(authUseCase)
+	schemaMigrationRepository, err := mysql.NewSchemaMigrationRepository(db)
+	if err != nil {
+		return nil, err
+	}
+	migrationUseCase := usecase.NewMigrationUseCase(schemaMigrationRepository, syncRunRepository, notificationRepository)
+	migrateCommand := cli.NewMigrateCommand(migrationUseCase)
+	runUseCase := usecase.NewRunUseCase(syncRunRepository)
+	runsCommand := cli.NewRunsCommand(runUseCase)
+	command, err := ProvideRootCommand(database, rootCommand, accountCommand, campaignCommand, masterCommand, authCommand, migrateCommand, runsCommand)
 	if err != nil {
 		return nil, err
 	}
@@ -92,20 +128,39 @@ func ProvideSecretsManager(sm *secrets.AWSSecretsManager) secrets.Manager {
 	return sm
 }
 
+// ProvideSecretsWriter はシークレットの書き込み用のWriterインターフェースを提供します
+func ProvideSecretsWriter(sm *secrets.AWSSecretsManager) secrets.Writer {
+	return sm
+}
+
 // ProvideDatabaseConnection はデータベース接続を提供します
 func ProvideDatabaseConnection(db *mysql.Database) *gorm.DB {
 	return db.DB
 }
 
+// ProvideTransactionManager はデータベース接続のトランザクションを使用するTransactionManagerを提供します
+func ProvideTransactionManager(db *mysql.Database) repository.TransactionManager {
+	return db
+}
+
 // ProvideRootCommand はルートコマンドを提供します
+// 起動時の自動マイグレーションは、migrate コマンド以外のコマンドの実行前に行います
 func ProvideRootCommand(
+	db *mysql.Database,
 	rootCmd *cli.RootCommand,
 	accountCmd *cli.AccountCommand,
 	campaignCmd *cli.CampaignCommand,
 	masterCmd *cli.MasterCommand,
+	authCmd *cli.AuthCommand,
+	migrateCmd *cli.MigrateCommand,
+	runsCmd *cli.RunsCommand,
 ) (*cobra.Command, error) {
 	rootCmd.Cmd.AddCommand(accountCmd.Cmd)
 	rootCmd.Cmd.AddCommand(campaignCmd.Cmd)
 	rootCmd.Cmd.AddCommand(masterCmd.Cmd)
+	rootCmd.Cmd.AddCommand(authCmd.Cmd)
+	rootCmd.Cmd.AddCommand(migrateCmd.Cmd)
+	rootCmd.Cmd.AddCommand(runsCmd.Cmd)
+	rootCmd.SetAutoMigrate(db.AutoMigrate, migrateCmd.Cmd)
 	return rootCmd.Cmd, nil
 }
@@ -1,13 +1,14 @@
 package cli
 
 import (
-	"context"
+	"fmt"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 )
 
 // NewAccountCommand はアカウントコマンドを作成します
@@ -17,28 +18,50 @@ func NewAccountCommand(accountUseCase *usecase.AccountUseCase) *AccountCommand {
 		accountIDs []int
 		syncMode   string
 		force      bool
+		source     string
+		prune      bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "account",
 		Short: "アカウント情報を同期します",
-		Long:  `外部APIからアカウント情報を取得し、データベースに保存します。`,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			ctx := context.Background()
+		Long: `外部APIからアカウント情報を取得し、データベースに保存します。
+--source api2 を指定すると、ExternalAPI2のマネージャーアカウント配下を再帰的にたどり、顧客アカウントを同期します。
+上流から削除されたアカウントは論理削除します（--prune を指定した場合は物理削除します）。`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
 			startTime := time.Now()
 
-			log.Info().Ints("account_ids", accountIDs).Str("sync_mode", syncMode).Bool("force", force).Msg("アカウント同期コマンドを実行します")
+			log.Info().Ints("account_ids", accountIDs).Str("sync_mode", syncMode).Bool("force", force).Str("source", source).Bool("prune", prune).Msg("アカウント同期コマンドを実行します")
+
+			opts := usecase.AccountSyncOptions{Source: source, Mode: syncMode, Prune: prune}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			if len(accountIDs) > 0 && source != entity.SourceAPI1 {
+				return fmt.Errorf("--id は --source %s の場合のみ指定できます", entity.SourceAPI1)
+			}
+			if len(accountIDs) > 0 && prune {
+				// 指定したアカウントのみの同期では削除されたアカウントを検出できない
+				return fmt.Errorf("--prune は --id と同時に指定できません")
+			}
 
 			// アカウント情報の同期
 			// 引数に基づいて処理を分岐
 			var err error
-			if len(accountIDs) > 0 {
+			switch {
+			case len(accountIDs) > 0:
 				// 特定のアカウントのみ同期
 				log.Info().Ints("account_ids", accountIDs).Msg("指定されたアカウントのみ同期します")
 				err = accountUseCase.SyncAccountsByIDs(ctx, accountIDs)
-			} else {
-				// 全アカウント同期
-				err = accountUseCase.SyncAccounts(ctx)
+			case syncMode == usecase.SyncModeDiff && force:
+				// 強制同期の場合は差分を取らずに全件を上書き
+				log.Info().Msg("強制同期フラグが指定されているため全件同期します")
+				opts.Mode = usecase.SyncModeFull
+				err = accountUseCase.SyncAccountsWithOptions(ctx, opts)
+			default:
+				// 同期モード・取得元に応じて同期
+				err = accountUseCase.SyncAccountsWithOptions(ctx, opts)
 			}
 
 			if err != nil {
@@ -56,6 +79,8 @@ func NewAccountCommand(accountUseCase *usecase.AccountUseCase) *AccountCommand {
 	cmd.Flags().IntSliceVar(&accountIDs, "id", []int{}, "同期するアカウントID（指定しない場合は全アカウント）")
 	cmd.Flags().StringVar(&syncMode, "mode", "full", "同期モード（full: 全同期, diff: 差分同期）")
 	cmd.Flags().BoolVar(&force, "force", false, "強制同期フラグ（既存データを上書き）")
+	cmd.Flags().StringVar(&source, "source", entity.SourceAPI1, "アカウントの取得元（api1: ExternalAPI1, api2: ExternalAPI2のマネージャーアカウント配下）")
+	cmd.Flags().BoolVar(&prune, "prune", false, "上流から削除されたアカウントを論理削除ではなく物理削除する")
 
 	return &AccountCommand{Cmd: cmd}
 }
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+)
+
+// NewAuthCommand は認可コマンドを作成します
+func NewAuthCommand(authUseCase *usecase.AuthUseCase) *AuthCommand {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "外部APIの認可を行います",
+		Long:  `外部APIのOAuth2認可を行い、同期コマンドで使用する認証情報を取得します。`,
+	}
+
+	cmd.AddCommand(newAuthAPI2Command(authUseCase))
+
+	return &AuthCommand{Cmd: cmd}
+}
+
+// newAuthAPI2Command は外部API2の認可コマンドを作成します
+func newAuthAPI2Command(authUseCase *usecase.AuthUseCase) *cobra.Command {
+	// フラグ変数の定義
+	var (
+		port         int
+		clientID     string
+		clientSecret string
+		save         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "api2",
+		Short: "ExternalAPI2のリフレッシュトークンを取得します",
+		Long: `ExternalAPI2（Google Ads）のOAuth2認可コードフローを実行し、リフレッシュトークンを取得します。
+表示されたURLをブラウザで開いて認可すると、ローカルポートでリダイレクトを受け取り、PKCEで認可コードを交換します。
+--save を指定すると、取得した認証情報を external_api2.oauth2_secret_id のシークレットに保存します。`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			out := cmd.OutOrStdout()
+
+			log.Info().Int("port", port).Bool("save", save).Msg("ExternalAPI2の認可コマンドを実行します")
+
+			refreshToken, err := authUseCase.AuthorizeAPI2(ctx, usecase.AuthorizeAPI2Options{
+				Port:         port,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+				Save:         save,
+				OnAuthURL: func(url string) {
+					_, _ = fmt.Fprintln(out, "以下のURLをブラウザで開いて認可してください:")
+					_, _ = fmt.Fprintln(out, url)
+				},
+			})
+			if refreshToken != "" {
+				_, _ = fmt.Fprintln(out, "リフレッシュトークン:")
+				_, _ = fmt.Fprintln(out, refreshToken)
+			}
+			if err != nil {
+				return err
+			}
+
+			if save {
+				_, _ = fmt.Fprintln(out, "認証情報をシークレットに保存しました")
+			}
+			return nil
+		},
+	}
+
+	// フラグの設定
+	cmd.Flags().IntVar(&port, "port", 0, "リダイレクトを受け取るローカルポート（0の場合は空いているポート）")
+	cmd.Flags().StringVar(&clientID, "client-id", "", "OAuth2クライアントID（指定しない場合は設定・Secret Managerの値）")
+	cmd.Flags().StringVar(&clientSecret, "client-secret", "", "OAuth2クライアントシークレット（指定しない場合は設定・Secret Managerの値）")
+	cmd.Flags().BoolVar(&save, "save", false, "取得した認証情報を external_api2.oauth2_secret_id のシークレットに保存する")
+
+	return cmd
+}
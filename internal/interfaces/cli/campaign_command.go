@@ -1,57 +1,76 @@
 package cli
 
 import (
-	"context"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
 )
 
 // NewCampaignCommand はキャンペーンコマンドを作成します
 func NewCampaignCommand(campaignUseCase *usecase.CampaignUseCase) *CampaignCommand {
 	// フラグ変数の定義
 	var (
-		accountIDs  string
-		status      string
-		parallelNum int
-		force       bool
+		source           string
+		accountIDs       string
+		status           string
+		parallelNum      int
+		continueOnError  bool
+		failureThreshold float64
+		force            bool
+		prune            bool
+		resume           uint
+		full             bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "campaign",
 		Short: "キャンペーン情報を同期します",
-		Long:  `アカウントごとに並列処理を行い、外部APIからキャンペーン情報を取得し、データベースに保存します。`,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			ctx := context.Background()
+		Long: `アカウントごとに並列処理を行い、外部APIからキャンペーン情報を取得し、データベースに保存します。
+取得に成功したアカウントごとに、上流から削除されたキャンペーンを論理削除します（--prune を指定した場合は物理削除します）。
+前回の同期で記録した基準日時以降に更新されたキャンペーンのみを取得します（--full を指定した場合は全件を取得し、基準日時を取り直します）。
+外部API2（--source api2）は更新日時による絞り込みに対応していないため、--full の指定に関わらず常に全件を取得します。
+上流から削除されたキャンペーンの検出は、全件を取得したアカウントのみで行います。
+保存が完了したアカウントは実行履歴のチェックポイントとして記録し、--resume に中断した実行のIDを指定すると完了済みのアカウントを読み飛ばして再開します。`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
 			startTime := time.Now()
 
-			log.Info().Str("account_ids", accountIDs).Str("status", status).Int("parallel_num", parallelNum).Bool("force", force).Msg("キャンペーン同期コマンドを実行します")
+			log.Info().Str("source", source).Str("account_ids", accountIDs).Str("status", status).Int("parallel_num", parallelNum).Bool("force", force).Bool("prune", prune).Uint("resume", resume).Bool("full", full).Msg("キャンペーン同期コマンドを実行します")
 
-			// キャンペーン情報の同期
-			// 引数に基づいて処理を分岐
-			var err error
-			if accountIDs != "" {
+			// フラグのパース
+			ids, err := parseAccountIDs(accountIDs)
+			if err != nil {
+				return err
+			}
+			opts := usecase.CampaignSyncOptions{
+				Source:     source,
+				AccountIDs: ids,
+				Statuses:   parseStatuses(status),
+				Parallel:   parallelNum,
+
+				ContinueOnError:  continueOnError,
+				FailureThreshold: failureThreshold,
+				Prune:            prune,
+				ResumeRunID:      resume,
+				Full:             full,
+			}
+
+			// 引数に基づいて同期対象を絞り込み
+			if len(opts.AccountIDs) > 0 {
 				// 特定のアカウントのキャンペーンのみ同期
-				log.Info().Str("account_ids", accountIDs).Msg("指定されたアカウントのキャンペーンのみ同期します")
-				// 実際の実装ではここでaccountIDsをパースして使う
-				// 例: "1,2,3" → [1, 2, 3]
-				err = campaignUseCase.SyncCampaigns(ctx)
-			} else if status != "" {
+				log.Info().Uints("account_ids", opts.AccountIDs).Msg("指定されたアカウントのキャンペーンのみ同期します")
+			}
+			if len(opts.Statuses) > 0 {
 				// 特定ステータスのキャンペーンのみ同期
-				log.Info().Str("status", status).Msg("指定されたステータスのキャンペーンのみ同期します")
-				// 実際の実装ではここでstatusを使った処理を行う
-				err = campaignUseCase.SyncCampaigns(ctx)
-			} else {
-				// 全キャンペーン同期
-				// 並列数を設定する場合はここでparallelNumを使う
-				log.Info().Int("parallel_num", parallelNum).Msg("并列数を設定して同期します")
-				err = campaignUseCase.SyncCampaigns(ctx)
+				log.Info().Strs("statuses", opts.Statuses).Msg("指定されたステータスのキャンペーンのみ同期します")
 			}
 
-			if err != nil {
+			// キャンペーン情報の同期
+			if err := campaignUseCase.SyncCampaignsWithOptions(ctx, opts); err != nil {
 				log.Error().Err(err).Msg("キャンペーン同期に失敗しました")
 				return err
 			}
@@ -63,10 +82,18 @@ func NewCampaignCommand(campaignUseCase *usecase.CampaignUseCase) *CampaignComma
 	}
 
 	// フラグの設定
+	cmd.Flags().StringVar(&source, "source", entity.SourceAPI1, "キャンペーンの取得元（api1: 外部API1, api2: 外部API2）")
 	cmd.Flags().StringVar(&accountIDs, "account-ids", "", "同期するアカウントID（カンマ区切り、例: '1,2,3'）、空の場合は全アカウント")
-	cmd.Flags().StringVar(&status, "status", "", "同期するキャンペーンのステータス（active, paused, completedなど）")
-	cmd.Flags().IntVar(&parallelNum, "parallel", 5, "並列処理数（1-10）")
+	cmd.Flags().StringVar(&status, "status", "", "同期するキャンペーンのステータス（カンマ区切り、例: 'active,paused'）、空の場合は全ステータス")
+	cmd.Flags().IntVar(&parallelNum, "parallel", usecase.DefaultParallel, "並列処理数（1-10）")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "一部のアカウントで失敗しても他のアカウントの処理を継続する")
+	cmd.Flags().Float64Var(&failureThreshold, "failure-threshold", 0, "失敗を許容するアカウントの割合（0.0-1.0）、超えた場合は異常終了する")
 	cmd.Flags().BoolVar(&force, "force", false, "強制同期フラグ（既存データを上書き）")
+	cmd.Flags().BoolVar(&prune, "prune", false, "上流から削除されたキャンペーンを論理削除ではなく物理削除する")
+	cmd.Flags().BoolVar(&full, "full", false, "差分同期の基準日時を使用せずに全てのキャンペーンを取得し、基準日時を取り直す（api2 は常に全件を取得するため影響しない）")
+	cmd.Flags().UintVar(&resume, "resume", 0, "中断した実行の実行履歴ID（runs list で確認）、同期が完了したアカウントを読み飛ばして再開する")
+
+	cmd.AddCommand(newCampaignHistoryCommand(campaignUseCase))
 
 	return &CampaignCommand{Cmd: cmd}
 }
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+)
+
+// newCampaignHistoryCommand はキャンペーンの変更履歴を表示するコマンドを作成します
+func newCampaignHistoryCommand(campaignUseCase *usecase.CampaignUseCase) *cobra.Command {
+	var (
+		campaignID uint
+		source     string
+		at         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "キャンペーンの変更履歴を表示します",
+		Long: `同期によって記録されたキャンペーンの予算・ステータス・期間の変更履歴を、版ごとの有効期間とともに表示します。
+取得元ごとにキャンペーンIDの空間が異なるため、--source で取得元を指定します。
+--at を指定すると、その日時に有効だった版のみを表示します。`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if campaignID == 0 {
+				return fmt.Errorf("--id にキャンペーンIDを指定してください")
+			}
+
+			var histories []entity.CampaignHistory
+			if at != "" {
+				t, err := parseDateTime(at)
+				if err != nil {
+					return err
+				}
+				history, err := campaignUseCase.GetCampaignHistoryAt(ctx, source, campaignID, t)
+				if err != nil {
+					return err
+				}
+				histories = []entity.CampaignHistory{*history}
+			} else {
+				var err error
+				histories, err = campaignUseCase.GetCampaignHistory(ctx, source, campaignID)
+				if err != nil {
+					return err
+				}
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tVALID FROM\tVALID TO\tSTATUS\tBUDGET\tSTART DATE\tEND DATE\tNAME")
+			for _, history := range histories {
+				validTo := "-"
+				if history.ValidTo != nil {
+					validTo = model.FormatJST(*history.ValidTo)
+				}
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.2f\t%s\t%s\t%s\n",
+					history.Version,
+					model.FormatJST(history.ValidFrom),
+					validTo,
+					history.Status,
+					history.Budget,
+					formatDate(history.StartDate),
+					formatDate(history.EndDate),
+					history.Name,
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().UintVar(&campaignID, "id", 0, "変更履歴を表示するキャンペーンID")
+	cmd.Flags().StringVar(&source, "source", entity.SourceAPI1, "キャンペーンの取得元（api1: 外部API1, api2: 外部API2）")
+	cmd.Flags().StringVar(&at, "at", "", "指定した日時に有効だった版のみを表示（例: '2024-05-01'（JSTの0時）、'2024-05-01T12:00:00+09:00'）")
+	return cmd
+}
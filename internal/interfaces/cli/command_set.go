@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 )
 
 // RootCommand はルートコマンドを表します
 type RootCommand struct {
 	Cmd *cobra.Command
+
+	autoMigrate     func(ctx context.Context) error // 各コマンドの実行前に行う自動マイグレーション
+	skipAutoMigrate *cobra.Command                  // 自動マイグレーションを行わないコマンド
 }
 
 // AccountCommand はアカウントコマンドを表します
@@ -37,3 +42,18 @@ type MasterCommand struct {
 	TimeoutSec  int
 	Force       bool
 }
+
+// AuthCommand は認可コマンドを表します
+type AuthCommand struct {
+	Cmd *cobra.Command
+}
+
+// MigrateCommand はマイグレーションコマンドを表します
+type MigrateCommand struct {
+	Cmd *cobra.Command
+}
+
+// RunsCommand は実行履歴コマンドを表します
+type RunsCommand struct {
+	Cmd *cobra.Command
+}
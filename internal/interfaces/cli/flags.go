@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout は日付のみを指定する場合の形式です
+const dateLayout = "2006-01-02"
+
+// jst は日時の入力・表示に使用するタイムゾーンです
+var jst = time.FixedZone("JST", 9*60*60)
+
+// parseAccountIDs はカンマ区切りのアカウントID文字列をパースします
+// 例: "1,2,3" → [1, 2, 3]（重複は除去し、指定順を維持します）
+func parseAccountIDs(value string) ([]uint, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	ids := make([]uint, 0, len(parts))
+	seen := make(map[uint]struct{}, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("アカウントIDの指定が不正です（空の要素が含まれています）: %q", value)
+		}
+
+		id, err := strconv.ParseUint(part, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("アカウントIDの指定が不正です（数値ではありません）: %q", part)
+		}
+		if id == 0 {
+			return nil, fmt.Errorf("アカウントIDの指定が不正です（0は指定できません）: %q", part)
+		}
+
+		if _, exists := seen[uint(id)]; exists {
+			continue
+		}
+		seen[uint(id)] = struct{}{}
+		ids = append(ids, uint(id))
+	}
+
+	return ids, nil
+}
+
+// parseStatuses はカンマ区切りのステータス文字列をパースします
+// 例: "active, Paused" → ["active", "paused"]
+func parseStatuses(value string) []string {
+	var statuses []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			statuses = append(statuses, part)
+		}
+	}
+	return statuses
+}
+
+// parseDateTime は日付（JST）またはRFC3339形式の日時をパースします
+func parseDateTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateLayout, value, jst); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日時の指定が不正です（YYYY-MM-DD またはRFC3339形式で指定してください）: %q", value)
+	}
+	return t, nil
+}
+
+// parseDateTimeUntil は期間の終了として日付（JST）またはRFC3339形式の日時をパースします
+// 日付のみを指定した場合は、その日を含むように翌日の0時を返します
+func parseDateTimeUntil(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateLayout, value, jst); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	return parseDateTime(value)
+}
+
+// formatDate は日付をJSTで表示します（未設定の場合は -）
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(jst).Format(dateLayout)
+}
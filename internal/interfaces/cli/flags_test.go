@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAccountIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []uint
+		wantErr string
+	}{
+		{name: "未指定", value: "", want: nil},
+		{name: "空白のみ", value: "  ", want: nil},
+		{name: "カンマ区切り", value: "3,1,2", want: []uint{3, 1, 2}},
+		{name: "前後の空白を除去", value: " 1 , 2 ", want: []uint{1, 2}},
+		{name: "重複は除去して指定順を維持", value: "2,1,2,1", want: []uint{2, 1}},
+		{name: "空の要素", value: "1,,2", wantErr: "空の要素"},
+		{name: "末尾のカンマ", value: "1,", wantErr: "空の要素"},
+		{name: "数値ではない", value: "1,abc", wantErr: "数値ではありません"},
+		{name: "負の数", value: "-1", wantErr: "数値ではありません"},
+		{name: "0", value: "0", wantErr: "0は指定できません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := parseAccountIDs(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestParseStatuses(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "未指定", value: "", want: nil},
+		{name: "小文字に変換", value: "ACTIVE,Paused", want: []string{"active", "paused"}},
+		{name: "前後の空白を除去", value: " active , paused ", want: []string{"active", "paused"}},
+		{name: "空の要素は無視", value: "active,, ,paused,", want: []string{"active", "paused"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseStatuses(tt.value))
+		})
+	}
+}
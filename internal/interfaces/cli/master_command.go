@@ -14,19 +14,27 @@ import (
 func NewMasterCommand(masterUseCase *usecase.MasterUseCase) *MasterCommand {
 	// フラグ変数の定義
 	var (
-		accountIDs  string
-		parallelNum int
-		timeoutSec  int
-		force       bool
+		accountIDs       string
+		parallelNum      int
+		timeoutSec       int
+		continueOnError  bool
+		failureThreshold float64
+		force            bool
+		prune            bool
+		atomic           bool
+		resume           uint
+		full             bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "master",
 		Short: "マスター情報を同期します",
-		Long:  `アカウント情報とキャンペーン情報を順に同期します。`,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		Long: `アカウント情報とキャンペーン情報を順に同期します。
+--atomic を指定すると両方の書き込みを1つのトランザクションで実行し、途中で失敗した場合は全てロールバックします。
+--resume に中断した実行のIDを指定すると、アカウント情報を同期した後、キャンペーンの同期が完了したアカウントを読み飛ばして再開します。`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			// タイムアウト付きコンテキストの作成
-			ctx := context.Background()
+			ctx := cmd.Context()
 			if timeoutSec > 0 {
 				var cancel context.CancelFunc
 				ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutSec)*time.Second)
@@ -35,22 +43,31 @@ func NewMasterCommand(masterUseCase *usecase.MasterUseCase) *MasterCommand {
 
 			startTime := time.Now()
 
-			log.Info().Str("account_ids", accountIDs).Int("parallel_num", parallelNum).Int("timeout_sec", timeoutSec).Bool("force", force).Msg("マスター同期コマンドを実行します")
+			log.Info().Str("account_ids", accountIDs).Int("parallel_num", parallelNum).Int("timeout_sec", timeoutSec).Bool("force", force).Bool("prune", prune).Bool("atomic", atomic).Uint("resume", resume).Bool("full", full).Msg("マスター同期コマンドを実行します")
 
-			// マスター情報の同期
-			// 引数に基づいて処理を分岐
-			var err error
+			// フラグのパース
+			ids, err := parseAccountIDs(accountIDs)
+			if err != nil {
+				return err
+			}
 
-			// アカウントIDが指定されている場合の処理
-			if accountIDs != "" {
-				log.Info().Str("account_ids", accountIDs).Msg("指定されたアカウントのみ同期します")
-				// 実際の実装ではここでaccountIDsをパースして使う
-				// 例: "1,2,3" → [1, 2, 3]
+			// アカウントIDが指定されている場合はキャンペーン同期の対象を絞り込む
+			if len(ids) > 0 {
+				log.Info().Uints("account_ids", ids).Msg("指定されたアカウントのキャンペーンのみ同期します")
 			}
 
-			// 全ての情報を同期
-			log.Info().Int("parallel_num", parallelNum).Msg("并列数を設定して全情報を同期します")
-			err = masterUseCase.SyncAll(ctx)
+			// マスター情報の同期
+			log.Info().Int("parallel_num", parallelNum).Msg("並列数を設定して全情報を同期します")
+			err = masterUseCase.SyncAll(ctx, usecase.MasterSyncOptions{
+				AccountIDs:       ids,
+				Parallel:         parallelNum,
+				ContinueOnError:  continueOnError,
+				FailureThreshold: failureThreshold,
+				Prune:            prune,
+				Atomic:           atomic,
+				ResumeRunID:      resume,
+				Full:             full,
+			})
 
 			if err != nil {
 				log.Error().Err(err).Msg("マスター同期に失敗しました")
@@ -64,10 +81,16 @@ func NewMasterCommand(masterUseCase *usecase.MasterUseCase) *MasterCommand {
 	}
 
 	// フラグの設定
-	cmd.Flags().StringVar(&accountIDs, "account-ids", "", "同期するアカウントID（カンマ区切り、例: '1,2,3'）、空の場合は全アカウント")
-	cmd.Flags().IntVar(&parallelNum, "parallel", 5, "並列処理数（1-10）")
+	cmd.Flags().StringVar(&accountIDs, "account-ids", "", "キャンペーンを同期するアカウントID（カンマ区切り、例: '1,2,3'）、空の場合は全アカウント（アカウント情報は常に全件を同期する）")
+	cmd.Flags().IntVar(&parallelNum, "parallel", usecase.DefaultParallel, "並列処理数（1-10）")
 	cmd.Flags().IntVar(&timeoutSec, "timeout", 0, "タイムアウト時間（秒）、0の場合はタイムアウトなし")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "キャンペーン同期で一部のアカウントが失敗しても処理を継続する")
+	cmd.Flags().Float64Var(&failureThreshold, "failure-threshold", 0, "失敗を許容するアカウントの割合（0.0-1.0）、超えた場合は異常終了する")
 	cmd.Flags().BoolVar(&force, "force", false, "強制同期フラグ（既存データを上書き）")
+	cmd.Flags().BoolVar(&prune, "prune", false, "上流から削除されたアカウント・キャンペーンを論理削除ではなく物理削除する")
+	cmd.Flags().BoolVar(&atomic, "atomic", false, "アカウント同期とキャンペーン同期の書き込みを1つのトランザクションで実行し、失敗した場合は全てロールバックする")
+	cmd.Flags().BoolVar(&full, "full", false, "キャンペーン同期で差分同期の基準日時を使用せずに全てのキャンペーンを取得し、基準日時を取り直す（api2 は常に全件を取得するため影響しない）")
+	cmd.Flags().UintVar(&resume, "resume", 0, "中断した実行の実行履歴ID（runs list で確認）、キャンペーンの同期が完了したアカウントを読み飛ばして再開する")
 
 	return &MasterCommand{Cmd: cmd}
 }
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// NewMigrateCommand はマイグレーションコマンドを作成します
+func NewMigrateCommand(migrationUseCase *usecase.MigrationUseCase) *MigrateCommand {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "データベースのスキーマをマイグレーションします",
+		Long: `バージョン管理されたマイグレーションSQLでデータベースのスキーマを変更します。
+適用状況は schema_migrations テーブルに記録されます。`,
+	}
+
+	cmd.AddCommand(
+		newMigrateUpCommand(migrationUseCase),
+		newMigrateDownCommand(migrationUseCase),
+		newMigrateStatusCommand(migrationUseCase),
+	)
+
+	return &MigrateCommand{Cmd: cmd}
+}
+
+// newMigrateUpCommand は未適用のマイグレーションを適用するコマンドを作成します
+func newMigrateUpCommand(migrationUseCase *usecase.MigrationUseCase) *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "未適用のマイグレーションを適用します",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			log.Info().Int("steps", steps).Msg("マイグレーションを適用します")
+
+			applied, err := migrationUseCase.Up(cmd.Context(), steps)
+			printMigrations(cmd, "適用", applied)
+			return err
+		},
+	}
+
+	cmd.Flags().IntVar(&steps, "steps", 0, "適用するマイグレーションの件数（0の場合は全て）")
+	return cmd
+}
+
+// newMigrateDownCommand は適用済みのマイグレーションを取り消すコマンドを作成します
+func newMigrateDownCommand(migrationUseCase *usecase.MigrationUseCase) *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "適用済みのマイグレーションを新しい順に取り消します",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			log.Info().Int("steps", steps).Msg("マイグレーションを取り消します")
+
+			reverted, err := migrationUseCase.Down(cmd.Context(), steps)
+			printMigrations(cmd, "取り消し", reverted)
+			return err
+		},
+	}
+
+	cmd.Flags().IntVar(&steps, "steps", 1, "取り消すマイグレーションの件数")
+	return cmd
+}
+
+// newMigrateStatusCommand はマイグレーションの適用状況を表示するコマンドを作成します
+func newMigrateStatusCommand(migrationUseCase *usecase.MigrationUseCase) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "マイグレーションの適用状況を表示します",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			statuses, err := migrationUseCase.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+			for _, status := range statuses {
+				state, appliedAt := "pending", "-"
+				if status.Applied() {
+					state, appliedAt = "applied", model.FormatJST(*status.AppliedAt)
+				}
+				_, _ = fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// printMigrations は適用・取り消したマイグレーションを表示します
+func printMigrations(cmd *cobra.Command, action string, migrations []repository.SchemaMigration) {
+	out := cmd.OutOrStdout()
+	if len(migrations) == 0 {
+		_, _ = fmt.Fprintf(out, "%sするマイグレーションはありません\n", action)
+		return
+	}
+	for _, m := range migrations {
+		_, _ = fmt.Fprintf(out, "%sしました: %04d_%s\n", action, m.Version, m.Name)
+	}
+}
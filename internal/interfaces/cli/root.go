@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/rs/zerolog/log"
@@ -18,11 +19,12 @@ var (
 
 // NewRootCommand はルートコマンドを作成します
 func NewRootCommand() *RootCommand {
+	root := &RootCommand{}
 	rootCmd := &cobra.Command{
 		Use:   "go-cli-ddd",
 		Short: "広告管理CLIアプリケーション",
 		Long:  `Go 1.24.0、Cobra、GORM、Google Wireを使用したDDDとクリーンアーキテクチャに基づく広告管理CLIアプリケーションです。`,
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 			// 設定ファイルの読み込み
 			cfgOpts := config.NewConfigOptions(cfgFile, env)
 			cfg, err := config.LoadConfig(cfgOpts)
@@ -40,7 +42,7 @@ func NewRootCommand() *RootCommand {
 				Bool("debug", cfg.App.Debug).
 				Msg("アプリケーションを起動しました")
 
-			return nil
+			return root.runAutoMigrate(cmd)
 		},
 	}
 
@@ -51,7 +53,29 @@ func NewRootCommand() *RootCommand {
 	// 設定ファイルの読み込み
 	cobra.OnInitialize(initConfig)
 
-	return &RootCommand{Cmd: rootCmd}
+	root.Cmd = rootCmd
+	return root
+}
+
+// SetAutoMigrate は各コマンドの実行前に行う自動マイグレーションを設定します
+// skip に指定したコマンド（migrate コマンド）とそのサブコマンドでは、スキーマを明示的に操作するため実行しません
+func (c *RootCommand) SetAutoMigrate(migrate func(ctx context.Context) error, skip *cobra.Command) {
+	c.autoMigrate = migrate
+	c.skipAutoMigrate = skip
+}
+
+// runAutoMigrate は実行するコマンドが自動マイグレーションの対象であればマイグレーションを適用します
+func (c *RootCommand) runAutoMigrate(cmd *cobra.Command) error {
+	if c.autoMigrate == nil {
+		return nil
+	}
+	for parent := cmd; parent != nil; parent = parent.Parent() {
+		if parent == c.skipAutoMigrate {
+			log.Debug().Str("command", cmd.CommandPath()).Msg("自動マイグレーションを行いません")
+			return nil
+		}
+	}
+	return c.autoMigrate(cmd.Context())
 }
 
 // initConfig は設定ファイルを初期化します
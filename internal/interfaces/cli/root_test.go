@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCommandAutoMigrate(t *testing.T) {
+	root := NewRootCommand()
+	migrateCmd := &cobra.Command{Use: "migrate"}
+	migrateUpCmd := &cobra.Command{Use: "up"}
+	migrateCmd.AddCommand(migrateUpCmd)
+	campaignCmd := &cobra.Command{Use: "campaign"}
+	root.Cmd.AddCommand(migrateCmd, campaignCmd)
+
+	var calls int
+	root.SetAutoMigrate(func(context.Context) error {
+		calls++
+		return nil
+	}, migrateCmd)
+
+	// migrate コマンドとそのサブコマンドでは自動マイグレーションを行わない
+	assert.NoError(t, root.runAutoMigrate(migrateCmd))
+	assert.NoError(t, root.runAutoMigrate(migrateUpCmd))
+	assert.Equal(t, 0, calls)
+
+	assert.NoError(t, root.runAutoMigrate(campaignCmd))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yuru-sha/go-cli-ddd/internal/application/usecase"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/entity"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/model"
+	"github.com/yuru-sha/go-cli-ddd/internal/domain/repository"
+)
+
+// NewRunsCommand は実行履歴コマンドを作成します
+func NewRunsCommand(runUseCase *usecase.RunUseCase) *RunsCommand {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "コマンドの実行履歴を表示します",
+		Long:  `sync_runs テーブルに保存されたコマンドの実行履歴（処理結果・件数・処理時間・エラーの概要）を表示します。`,
+	}
+
+	cmd.AddCommand(
+		newRunsListCommand(runUseCase),
+		newRunsShowCommand(runUseCase),
+	)
+
+	return &RunsCommand{Cmd: cmd}
+}
+
+// newRunsListCommand は実行履歴の一覧を表示するコマンドを作成します
+func newRunsListCommand(runUseCase *usecase.RunUseCase) *cobra.Command {
+	var (
+		process string
+		status  string
+		from    string
+		to      string
+		limit   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "実行履歴の一覧を開始時刻の新しい順に表示します",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filter := repository.SyncRunFilter{Process: process, Status: status, Limit: limit}
+			if from != "" {
+				t, err := parseDateTime(from)
+				if err != nil {
+					return err
+				}
+				filter.From = &t
+			}
+			if to != "" {
+				t, err := parseDateTimeUntil(to)
+				if err != nil {
+					return err
+				}
+				filter.To = &t
+			}
+
+			runs, err := runUseCase.ListRuns(cmd.Context(), filter)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tPROCESS\tSTATUS\tSTARTED AT\tDURATION\tTOTAL\tSUCCESS\tERROR\tRECORDS")
+			for _, run := range runs {
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n",
+					run.ID,
+					run.Process,
+					run.Status,
+					model.FormatJST(run.StartedAt),
+					run.Duration().Round(time.Millisecond),
+					run.TotalCount,
+					run.SuccessCount,
+					run.ErrorCount,
+					run.TotalRecords,
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&process, "process", "", "実行したコマンドで絞り込み（前方一致、例: 'campaign sync'）")
+	cmd.Flags().StringVar(&status, "status", "", "処理結果のステータスで絞り込み（success, failed, running, cancelled のいずれか）")
+	cmd.Flags().StringVar(&from, "from", "", "開始時刻の下限（例: '2024-05-01'（JSTの0時）、RFC3339形式）")
+	cmd.Flags().StringVar(&to, "to", "", "開始時刻の上限（例: '2024-05-31'（その日を含む）、RFC3339形式）")
+	cmd.Flags().IntVar(&limit, "limit", usecase.DefaultRunListLimit, "表示する件数の上限（0の場合は全件）")
+	return cmd
+}
+
+// newRunsShowCommand は実行履歴の詳細を表示するコマンドを作成します
+func newRunsShowCommand(runUseCase *usecase.RunUseCase) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "実行履歴の詳細を表示します",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 0)
+			if err != nil || id == 0 {
+				return fmt.Errorf("実行履歴のIDの指定が不正です: %q", args[0])
+			}
+
+			run, err := runUseCase.GetRun(cmd.Context(), uint(id))
+			if err != nil {
+				return err
+			}
+
+			printRun(cmd, run)
+			return nil
+		},
+	}
+}
+
+// printRun は実行履歴の詳細を表示します
+func printRun(cmd *cobra.Command, run *entity.SyncRun) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{"ID", run.ID},
+		{"Process", run.Process},
+		{"Status", run.Status},
+		{"AccountIds", valueOrDash(run.AccountIDs)},
+		{"From", valueOrDash(run.DateFrom)},
+		{"To", valueOrDash(run.DateTo)},
+		{"Start", model.FormatJST(run.StartedAt)},
+		{"End", model.FormatJST(run.FinishedAt)},
+		{"Time", run.Duration().Round(time.Millisecond)},
+		{"Total", run.TotalCount},
+		{"Success", run.SuccessCount},
+		{"Error", run.ErrorCount},
+		{"Total Records", run.TotalRecords},
+		{"Inserted", run.InsertedCount},
+		{"Updated", run.UpdatedCount},
+		{"Unchanged", run.UnchangedCount},
+		{"Removed", run.RemovedCount},
+		{"Pages", run.PageCount},
+		{"Failed AccountIds", valueOrDash(run.FailedAccountIDs)},
+		{"Error Message", valueOrDash(run.ErrorMessage)},
+	}
+	for _, field := range fields {
+		_, _ = fmt.Fprintf(w, "%s:\t%v\n", field.name, field.value)
+	}
+	_ = w.Flush()
+}
+
+// valueOrDash は空文字の場合に - を返します
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}